@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"HyLauncher/internal/patch"
+)
+
+// Applies a local .pwr file with the built-in applier, for checking patches
+// against the output of butler apply
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: apply-patch <patch.pwr> <dir>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), flag.Arg(1)); err != nil {
+		fmt.Fprintf(os.Stderr, "Apply failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(pwrFile, dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	stagingDir, err := os.MkdirTemp(filepath.Dir(dir), ".apply-patch-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stagingDir)
+
+	last := -1
+	err = patch.ApplyNative(context.Background(), pwrFile, dir, stagingDir, func(done, total int64) {
+		if total <= 0 {
+			return
+		}
+		if percent := int(done * 100 / total); percent != last {
+			last = percent
+			fmt.Fprintf(os.Stderr, "\r%3d%%", percent)
+		}
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Applied %s to %s\n", pwrFile, dir)
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"HyLauncher/internal/config"
+	"HyLauncher/internal/env"
+	"HyLauncher/internal/game"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the preview as JSON")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: preview-launch [-json] [profile-id]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	profileID := flag.Arg(0)
+	if err := run(profileID, *asJSON); err != nil {
+		fmt.Fprintf(os.Stderr, "Preview failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(profileID string, asJSON bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	env.SetInstallDir(cfg.Settings.GameDir)
+
+	if profileID == "" {
+		profileID = cfg.CurrentProfile
+	}
+
+	var profile *config.Profile
+	for i := range cfg.Profiles {
+		if cfg.Profiles[i].ID == profileID {
+			profile = &cfg.Profiles[i]
+			break
+		}
+	}
+	if profile == nil {
+		return fmt.Errorf("profile %q not found", profileID)
+	}
+
+	opts, err := game.NewLaunchOptions(cfg, *profile, profile.Name)
+	if err != nil {
+		return err
+	}
+
+	preview, err := game.PreviewLaunch(opts)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(preview)
+	}
+
+	fmt.Printf("Executable:  %s\n", preview.Executable)
+	fmt.Printf("Working dir: %s\n", preview.WorkingDir)
+	fmt.Println("Arguments:")
+	for _, arg := range preview.Args {
+		fmt.Printf("  %s\n", arg)
+	}
+	fmt.Println("Environment changes:")
+	for _, change := range preview.Env {
+		fmt.Printf("  %s=%s\n", change.Name, change.Value)
+	}
+	if preview.PreLaunchHook != "" {
+		fmt.Printf("Pre-launch hook (not run): %s\n", preview.PreLaunchHook)
+	}
+	fmt.Println()
+	fmt.Println(preview.Command)
+	return nil
+}
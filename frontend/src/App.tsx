@@ -5,13 +5,17 @@ import { ProfileSection } from './components/ProfileCard';
 import { UpdateOverlay } from './components/UpdateOverlay';
 import { ControlSection } from './components/ControlSection';
 import { DeleteConfirmationModal } from './components/DeleteConfirmationModal';
+import { DeleteProfileModal } from './components/DeleteProfileModal';
+import { UUIDChangeModal } from './components/UUIDChangeModal';
 import { ErrorModal } from './components/ErrorModal';
 import { DiagnosticsModal } from './components/DiagnosticsModal';
 import { SettingsModal } from './components/SettingsModal';
+import { ServerModal } from './components/ServerModal';
+import { ProfileSettingsModal } from './components/ProfileSettingsModal';
 
-import { DownloadAndLaunch, OpenFolder, GetVersions, GetCurrentProfile, GetProfiles, SetCurrentProfile, AddProfile, UpdateProfile, DeleteProfile, DeleteGame, RunDiagnostics, SaveDiagnosticReport, Update, StopGame, GetSettings } from '../wailsjs/go/app/App';
+import { DownloadAndLaunch, OpenFolder, GetVersions, GetCurrentProfile, GetProfiles, SetCurrentProfile, AddProfile, UpdateProfile, CheckProfileUUID, DeleteProfile, DeleteGame, RunDiagnostics, SaveDiagnosticReport, Update, StopGame, GetSettings } from '../wailsjs/go/app/App';
 import { EventsOn } from '../wailsjs/runtime/runtime';
-import { config, app } from '../wailsjs/go/models';
+import { config, app, game } from '../wailsjs/go/models';
 import { NewsSection } from './components/NewsSection';
 import { useWindowState } from './hooks/useWindowState';
 
@@ -28,6 +32,7 @@ const App: React.FC = () => {
   const [status, setStatus] = useState<string>("Ready to play");
   const [isDownloading, setIsDownloading] = useState<boolean>(false);
   const [isPlaying, setIsPlaying] = useState<boolean>(false);
+  const [applied, setApplied] = useState<string[]>([]);
 
   const [currentFile, setCurrentFile] = useState<string>("");
   const [downloadSpeed, setDownloadSpeed] = useState<string>("");
@@ -41,6 +46,10 @@ const App: React.FC = () => {
   const [showDelete, setShowDelete] = useState<boolean>(false);
   const [showDiag, setShowDiag] = useState<boolean>(false);
   const [showSettings, setShowSettings] = useState<boolean>(false);
+  const [showServer, setShowServer] = useState<boolean>(false);
+  const [profileToDelete, setProfileToDelete] = useState<config.Profile | null>(null);
+  const [profileToEdit, setProfileToEdit] = useState<config.Profile | null>(null);
+  const [pendingRename, setPendingRename] = useState<{ id: string; name: string; change: game.UUIDChange } | null>(null);
   const [error, setError] = useState<any>(null);
   const [channel, setChannel] = useState<string>('release');
 
@@ -115,15 +124,17 @@ const App: React.FC = () => {
       }
     });
 
-    const gameLaunchedListener = EventsOn('game-launched', () => {
+    const gameLaunchedListener = EventsOn('game-launched', (event: { settings?: game.LaunchSettings }) => {
       setIsPlaying(true);
       setIsDownloading(false);
       setStatus("Game Running...");
+      setApplied(event?.settings?.applied || []);
     });
 
     const gameClosedListener = EventsOn('game-closed', () => {
       setIsPlaying(false);
       setStatus("Ready to play");
+      setApplied([]);
     });
 
     return () => {
@@ -167,12 +178,45 @@ const App: React.FC = () => {
   };
 
   const handleProfileUpdate = async (id: string, name: string) => {
-    await UpdateProfile(id, name);
+    const profile = profiles.find(p => p.id === id);
+    if (!profile) return;
+    try {
+      // Renaming changes the UUID in offline mode, ask before worlds lose the player
+      const change = await CheckProfileUUID(config.Profile.createFrom({ ...profile, name }));
+      if (change.changed) {
+        setPendingRename({ id, name, change });
+        return;
+      }
+      await UpdateProfile(id, name, false);
+    } catch (err) {
+      console.error("Failed to rename profile:", err);
+    }
     await refreshProfiles();
   };
 
-  const handleProfileDelete = async (id: string) => {
-    await DeleteProfile(id);
+  const confirmRename = async () => {
+    if (!pendingRename) return;
+    try {
+      await UpdateProfile(pendingRename.id, pendingRename.name, true);
+    } catch (err) {
+      console.error("Failed to rename profile:", err);
+    }
+    setPendingRename(null);
+    await refreshProfiles();
+  };
+
+  const handleProfileDelete = (id: string) => {
+    setProfileToDelete(profiles.find(p => p.id === id) || null);
+  };
+
+  const confirmProfileDelete = async (removeData: boolean) => {
+    if (!profileToDelete) return;
+    try {
+      await DeleteProfile(profileToDelete.id, removeData);
+    } catch (err) {
+      console.error("Failed to delete profile:", err);
+    }
+    setProfileToDelete(null);
     await refreshProfiles();
   };
 
@@ -195,6 +239,7 @@ const App: React.FC = () => {
             onProfileAdd={handleProfileAdd}
             onProfileUpdate={handleProfileUpdate}
             onProfileDelete={handleProfileDelete}
+            onProfileSettings={(id) => setProfileToEdit(profiles.find(p => p.id === id) || null)}
           />
 
           <NewsSection />
@@ -220,16 +265,22 @@ const App: React.FC = () => {
           downloaded={downloaded}
           total={total}
           currentFile={currentFile}
+          applied={isPlaying ? applied : []}
           actions={{
             openFolder: OpenFolder,
             showDiagnostics: () => setShowDiag(true),
             showDelete: () => setShowDelete(true),
-            showSettings: () => setShowSettings(true)
+            showSettings: () => setShowSettings(true),
+            showServer: () => setShowServer(true)
           }}
         />
       </main>
 
       {showSettings && <SettingsModal onClose={() => { setShowSettings(false); checkGameUpdates(); }} />}
+      {showServer && <ServerModal onClose={() => setShowServer(false)} />}
+      {pendingRename && <UUIDChangeModal change={pendingRename.change} onConfirm={confirmRename} onCancel={() => setPendingRename(null)} />}
+      {profileToEdit && <ProfileSettingsModal profile={profileToEdit} onClose={() => setProfileToEdit(null)} onSaved={refreshProfiles} />}
+      {profileToDelete && <DeleteProfileModal profile={profileToDelete} onConfirm={confirmProfileDelete} onCancel={() => setProfileToDelete(null)} />}
       {showDelete && <DeleteConfirmationModal onConfirm={() => { DeleteGame(); setShowDelete(false); }} onCancel={() => setShowDelete(false)} />}
       {showDiag && <DiagnosticsModal onClose={() => setShowDiag(false)} onRunDiagnostics={RunDiagnostics} onSaveDiagnostics={SaveDiagnosticReport} />}
       {error && <ErrorModal error={error} onClose={() => setError(null)} />}
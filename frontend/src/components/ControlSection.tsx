@@ -1,6 +1,6 @@
 import React from 'react';
 import { motion } from 'framer-motion';
-import { FolderOpen, Activity, Settings, Trash } from 'lucide-react';
+import { FolderOpen, Activity, Settings, Trash, Server } from 'lucide-react';
 
 interface ControlSectionProps {
   onPlay: () => void;
@@ -13,16 +13,18 @@ interface ControlSectionProps {
   downloaded: number;
   total: number;
   currentFile: string;
+  applied?: string[];
   actions: {
     openFolder: () => void;
     showDiagnostics: () => void;
     showDelete: () => void;
     showSettings: () => void;
+    showServer: () => void;
   };
 }
 
 export const ControlSection: React.FC<ControlSectionProps> = ({
-  onPlay, isDownloading, isPlaying, isUpdateAvailable, progress, status, speed, downloaded, total, currentFile, actions
+  onPlay, isDownloading, isPlaying, isUpdateAvailable, progress, status, speed, downloaded, total, currentFile, applied = [], actions
 }) => {
 
   // Your original formatting helper
@@ -41,6 +43,7 @@ export const ControlSection: React.FC<ControlSectionProps> = ({
           <NavBtn onClick={actions.openFolder} icon={<FolderOpen size={20} />} />
           <NavBtn onClick={actions.showDiagnostics} icon={<Activity size={20} />} />
           <NavBtn onClick={actions.showSettings} icon={<Settings size={20} />} />
+          <NavBtn onClick={actions.showServer} icon={<Server size={20} />} />
           <NavBtn onClick={actions.showDelete} icon={<Trash size={20} />} />
         </div>
         <motion.button
@@ -71,7 +74,9 @@ export const ControlSection: React.FC<ControlSectionProps> = ({
           <div className="text-[11px] text-gray-400 font-mono">
             {speed && total > 0
               ? `${speed} • ${formatBytes(downloaded)} / ${formatBytes(total)}`
-              : currentFile || 'Ready'}
+              : applied.length > 0
+                ? <span className="block max-w-[520px] truncate" title={applied.join('\n')}>{applied.join(' • ')}</span>
+                : currentFile || 'Ready'}
           </div>
         </div>
         <div className="h-2 w-full bg-white/5 rounded-full overflow-hidden border border-white/5">
@@ -83,7 +88,7 @@ export const ControlSection: React.FC<ControlSectionProps> = ({
 };
 
 const NavBtn = ({ icon, onClick }: { icon: any, onClick?: () => void }) => (
-  <button onClick={onClick} className="w-[66px] h-[42px] cursor-pointer flex items-center justify-center bg-[#090909]/[0.55] backdrop-blur-xl border border-[#FFA845]/[0.10] rounded-[14px] hover:bg-[#FFA845]/[0.05] hover:border-[#FFA845]/[0.30] transition-all text-gray-400 hover:text-white">
+  <button onClick={onClick} className="flex-1 h-[42px] cursor-pointer flex items-center justify-center bg-[#090909]/[0.55] backdrop-blur-xl border border-[#FFA845]/[0.10] rounded-[14px] hover:bg-[#FFA845]/[0.05] hover:border-[#FFA845]/[0.30] transition-all text-gray-400 hover:text-white">
     {icon}
   </button>
 );
\ No newline at end of file
@@ -0,0 +1,73 @@
+import React, { useState } from 'react';
+import { motion, AnimatePresence } from 'framer-motion';
+import { config } from '../../wailsjs/go/models';
+
+interface DeleteProfileModalProps {
+  profile: config.Profile;
+  onConfirm: (removeData: boolean) => void;
+  onCancel: () => void;
+}
+
+export const DeleteProfileModal: React.FC<DeleteProfileModalProps> = ({
+  profile,
+  onConfirm,
+  onCancel,
+}) => {
+  const [removeData, setRemoveData] = useState(false);
+
+  return (
+    <AnimatePresence>
+      <motion.div
+        initial={{ opacity: 0 }}
+        animate={{ opacity: 1 }}
+        exit={{ opacity: 0 }}
+        className="fixed inset-0 bg-black/70 backdrop-blur-sm z-50 flex items-center justify-center p-4"
+      >
+        <motion.div
+          initial={{ scale: 0.85, y: 20, opacity: 0 }}
+          animate={{ scale: 1, y: 0, opacity: 1 }}
+          exit={{ scale: 0.85, y: 20, opacity: 0 }}
+          transition={{ type: "spring", damping: 20, stiffness: 300 }}
+          className="bg-[#0f0f0f] border border-[#FFA845]/20 rounded-2xl p-8 max-w-md w-full shadow-2xl"
+        >
+          <h2 className="text-2xl font-bold text-white mb-4">Delete profile?</h2>
+
+          <p className="text-gray-300 mb-6 leading-relaxed">
+            The profile <span className="text-white font-medium">{profile.name}</span> will be removed from the launcher.
+          </p>
+
+          <label className="flex items-start gap-3 mb-8 cursor-pointer">
+            <input
+              type="checkbox"
+              checked={removeData}
+              onChange={(e) => setRemoveData(e.target.checked)}
+              className="mt-1 accent-red-500"
+            />
+            <span className="text-sm text-gray-300">
+              Also delete its isolated UserData
+              <span className="block text-xs text-red-400 mt-1">
+                Worlds, settings and caches stored only for this profile are deleted permanently.
+                The shared UserData is never touched.
+              </span>
+            </span>
+          </label>
+
+          <div className="flex gap-4 justify-end">
+            <button
+              onClick={onCancel}
+              className="px-6 py-3 bg-[#1a1a1a] hover:bg-[#222] text-gray-300 rounded-lg transition-colors border border-white/10"
+            >
+              Cancel
+            </button>
+            <button
+              onClick={() => onConfirm(removeData)}
+              className="px-6 py-3 bg-red-600 hover:bg-red-700 text-white font-medium rounded-lg transition-colors shadow-lg shadow-red-900/30"
+            >
+              {removeData ? 'Delete Profile and Data' : 'Delete Profile'}
+            </button>
+          </div>
+        </motion.div>
+      </motion.div>
+    </AnimatePresence>
+  );
+};
@@ -28,6 +28,7 @@ interface DiagnosticReport {
     install_path: string;
     jre_installed: boolean;
     butler_installed: boolean;
+    butler_version?: string;
   };
   server_versions: {
     latest_version: number;
@@ -39,6 +40,14 @@ interface DiagnosticReport {
     install_directory: string;
     error?: string;
   };
+  graphics: {
+    session_type?: string;
+    wayland_display?: string;
+    display?: string;
+    display_backend: string;
+    gpu_offload: string;
+    env?: string[];
+  };
 }
 
 export const DiagnosticsModal: React.FC<DiagnosticsModalProps> = ({
@@ -102,6 +111,7 @@ Game Installed: ${report.local_installation.game_installed ? 'Yes' : 'No'}
 Current Version: ${report.local_installation.current_version}
 JRE Installed: ${report.local_installation.jre_installed ? 'Yes' : 'No'}
 Butler Installed: ${report.local_installation.butler_installed ? 'Yes' : 'No'}
+Butler Version: ${report.local_installation.butler_version || 'unknown'}
 Install Path: ${report.local_installation.install_path}
 
 --- Server Versions ---
@@ -110,6 +120,12 @@ Versions Found: ${report.server_versions.found_versions ? 'Yes' : 'No'}
 ${report.server_versions.error ? `Error: ${report.server_versions.error}` : ''}
 
 ${report.server_versions.checked_urls ? `Sample URLs:\n${report.server_versions.checked_urls.join('\n')}` : ''}
+
+--- Graphics ---
+Session Type: ${report.graphics.session_type || 'unknown'}
+Display Backend: ${report.graphics.display_backend || 'auto'}
+GPU Offload: ${report.graphics.gpu_offload || 'none'}
+${report.graphics.env ? `Environment:\n${report.graphics.env.join('\n')}` : ''}
 `;
   };
 
@@ -258,6 +274,12 @@ ${report.server_versions.checked_urls ? `Sample URLs:\n${report.server_versions.
                     <span className="text-xs text-gray-400">Butler Tool</span>
                     <StatusIcon status={report.local_installation.butler_installed} />
                   </div>
+                  {report.local_installation.butler_version && (
+                    <div className="flex items-center justify-between">
+                      <span className="text-xs text-gray-400">Butler Version</span>
+                      <span className="text-xs text-gray-200">{report.local_installation.butler_version}</span>
+                    </div>
+                  )}
                 </div>
               </div>
 
@@ -282,6 +304,30 @@ ${report.server_versions.checked_urls ? `Sample URLs:\n${report.server_versions.
                   )}
                 </div>
               </div>
+
+              {/* Graphics */}
+              <div className="bg-white/5 rounded-lg p-4 border border-white/5">
+                <h4 className="text-sm font-bold text-white mb-3">Graphics</h4>
+                <div className="space-y-2">
+                  <div className="flex items-center justify-between">
+                    <span className="text-xs text-gray-400">Session Type</span>
+                    <span className="text-xs text-gray-200">{report.graphics.session_type || 'unknown'}</span>
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <span className="text-xs text-gray-400">Display Backend</span>
+                    <span className="text-xs text-gray-200">{report.graphics.display_backend || 'auto'}</span>
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <span className="text-xs text-gray-400">GPU Offload</span>
+                    <span className="text-xs text-gray-200">{report.graphics.gpu_offload || 'none'}</span>
+                  </div>
+                  {report.graphics.env && (
+                    <div className="mt-2 p-2 bg-black/30 rounded text-xs text-gray-300 font-mono break-all">
+                      {report.graphics.env.map(v => <div key={v}>{v}</div>)}
+                    </div>
+                  )}
+                </div>
+              </div>
             </div>
           )}
         </div>
@@ -1,5 +1,5 @@
 import React, { useState } from 'react';
-import { Edit3, ChevronDown, ArrowUpCircle, Plus, Trash2, Check, X } from 'lucide-react';
+import { Edit3, ChevronDown, ArrowUpCircle, Plus, Trash2, Check, X, SlidersHorizontal } from 'lucide-react';
 import { config } from '../../wailsjs/go/models';
 
 interface ProfileProps {
@@ -12,11 +12,12 @@ interface ProfileProps {
   onProfileAdd: (name: string) => void;
   onProfileDelete: (id: string) => void;
   onProfileUpdate: (id: string, name: string) => void;
+  onProfileSettings: (id: string) => void;
 }
 
 export const ProfileSection: React.FC<ProfileProps> = ({
   currentProfile, profiles, currentVersion, updateAvailable, onUpdate,
-  onProfileChange, onProfileAdd, onProfileDelete, onProfileUpdate
+  onProfileChange, onProfileAdd, onProfileDelete, onProfileUpdate, onProfileSettings
 }) => {
   const [isOpen, setIsOpen] = useState(false);
   const [isAdding, setIsAdding] = useState(false);
@@ -113,6 +114,11 @@ export const ProfileSection: React.FC<ProfileProps> = ({
                         className="text-gray-400 hover:text-white cursor-pointer"
                         onClick={(e) => { e.stopPropagation(); setEditingId(p.id); setEditName(p.name); }}
                       />
+                      <SlidersHorizontal
+                        size={12}
+                        className="text-gray-400 hover:text-white cursor-pointer"
+                        onClick={(e) => { e.stopPropagation(); setIsOpen(false); onProfileSettings(p.id); }}
+                      />
                       {profiles.length > 1 && (
                         <Trash2
                           size={12}
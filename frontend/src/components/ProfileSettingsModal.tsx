@@ -0,0 +1,344 @@
+import React, { useState, useEffect } from 'react';
+import { motion } from 'framer-motion';
+import { User, X, Save, Loader2, Copy, MoveRight } from 'lucide-react';
+import { SaveProfile, MigrateUserData, CheckProfileUUID } from '../../wailsjs/go/app/App';
+import { Environment } from '../../wailsjs/runtime/runtime';
+import { config, game } from '../../wailsjs/go/models';
+import { UUIDChangeModal } from './UUIDChangeModal';
+
+interface ProfileSettingsModalProps {
+    profile: config.Profile;
+    onClose: () => void;
+    onSaved: () => void;
+}
+
+export const ProfileSettingsModal: React.FC<ProfileSettingsModalProps> = ({ profile, onClose, onSaved }) => {
+    const [draft, setDraft] = useState<config.Profile>(config.Profile.createFrom(profile));
+    const [envText, setEnvText] = useState<string>(formatEnv(profile.env));
+    const [saving, setSaving] = useState(false);
+    const [migrating, setMigrating] = useState(false);
+    const [error, setError] = useState<string | null>(null);
+    const [pendingChange, setPendingChange] = useState<{ profile: config.Profile; change: game.UUIDChange } | null>(null);
+    const [isLinux, setIsLinux] = useState(false);
+
+    useEffect(() => {
+        Environment().then(info => setIsLinux(info.platform === 'linux')).catch(() => setIsLinux(false));
+    }, []);
+
+    const update = (key: keyof config.Profile, value: any) => {
+        setDraft(prev => config.Profile.createFrom({ ...prev, [key]: value }));
+    };
+
+    const handleSave = async () => {
+        setError(null);
+        const env = parseEnv(envText);
+        if (typeof env === 'string') {
+            setError(env);
+            return;
+        }
+        const updated = config.Profile.createFrom({ ...draft, env });
+
+        setSaving(true);
+        try {
+            // A new UUID loses the player's progress on existing worlds, ask first
+            const change = await CheckProfileUUID(updated);
+            if (change.changed) {
+                setPendingChange({ profile: updated, change });
+                setSaving(false);
+                return;
+            }
+            await save(updated, false);
+        } catch (err) {
+            console.error("Failed to save profile:", err);
+            setError(String(err));
+            setSaving(false);
+        }
+    };
+
+    const save = async (updated: config.Profile, confirmUUIDChange: boolean) => {
+        setSaving(true);
+        try {
+            await SaveProfile(updated, confirmUUIDChange);
+            onSaved();
+            onClose();
+        } catch (err) {
+            console.error("Failed to save profile:", err);
+            setError(String(err));
+            setSaving(false);
+        }
+    };
+
+    const handleMigrate = async (move: boolean) => {
+        setMigrating(true);
+        setError(null);
+        try {
+            await MigrateUserData(profile.id, move);
+            update('isolatedUserData', true);
+            onSaved();
+        } catch (err) {
+            console.error("Failed to migrate user data:", err);
+            setError(String(err));
+        } finally {
+            setMigrating(false);
+        }
+    };
+
+    return (
+        <motion.div
+            initial={{ opacity: 0 }}
+            animate={{ opacity: 1 }}
+            exit={{ opacity: 0 }}
+            className="fixed inset-0 bg-black/80 backdrop-blur-sm flex items-center justify-center z-50 p-4"
+            onClick={onClose}
+        >
+            <motion.div
+                initial={{ scale: 0.9, opacity: 0 }}
+                animate={{ scale: 1, opacity: 1 }}
+                exit={{ scale: 0.9, opacity: 0 }}
+                onClick={(e) => e.stopPropagation()}
+                className="w-full max-w-2xl bg-[#090909]/95 backdrop-blur-xl rounded-2xl border border-[#FFA845]/20 overflow-hidden shadow-2xl max-h-[600px] flex flex-col"
+            >
+                {/* Header */}
+                <div className="p-6 border-b border-white/10 bg-gradient-to-r from-[#FFA845]/10 to-transparent flex justify-between items-center">
+                    <div className="flex items-center gap-3">
+                        <div className="p-2 rounded-lg bg-[#FFA845]/20">
+                            <User size={24} className="text-[#FFA845]" />
+                        </div>
+                        <div>
+                            <h3 className="text-lg font-bold text-white">{profile.name}</h3>
+                            <p className="text-xs text-gray-400">Profile settings</p>
+                        </div>
+                    </div>
+                    <button onClick={onClose} className="p-2 hover:bg-white/10 rounded-lg transition-colors text-gray-400 hover:text-white">
+                        <X size={20} />
+                    </button>
+                </div>
+
+                {/* Content */}
+                <div className="flex-1 p-8 overflow-y-auto space-y-6">
+                    <Section title="Player UUID" description="Servers and worlds know the player by this UUID">
+                        <div className="grid grid-cols-3 gap-2">
+                            {[
+                                ['profile', 'Profile ID'],
+                                ['offline', 'From nickname'],
+                                ['custom', 'Custom'],
+                            ].map(([value, label]) => (
+                                <button
+                                    key={value}
+                                    onClick={() => update('uuidMode', value)}
+                                    className={`px-3 py-2 rounded-lg border text-xs transition-colors ${(draft.uuidMode || 'profile') === value ? 'border-[#FFA845]/50 text-[#FFA845] bg-[#FFA845]/10' : 'border-white/10 text-gray-300 bg-black/40 hover:bg-white/5'}`}
+                                >
+                                    {label}
+                                </button>
+                            ))}
+                        </div>
+                        {draft.uuidMode === 'custom' && (
+                            <input
+                                type="text"
+                                value={draft.customUuid || ''}
+                                onChange={(e) => update('customUuid', e.target.value.trim())}
+                                placeholder="00000000-0000-0000-0000-000000000000"
+                                className="w-full mt-3 bg-black/40 border border-white/10 rounded-lg px-4 py-2 text-sm font-mono text-white focus:outline-none focus:border-[#FFA845]/50"
+                            />
+                        )}
+                        {draft.lastUuid && (
+                            <p className="text-xs text-gray-500 mt-3 font-mono select-text">Last launched as {draft.lastUuid}</p>
+                        )}
+                    </Section>
+
+                    <Section title="Launch" description="Commands run around the game client, quoted like a shell">
+                        <div className="space-y-3">
+                            <Field label="Wrapper command" placeholder="gamemoderun" value={draft.wrapperCommand} onChange={(v) => update('wrapperCommand', v)} />
+                            <Field label="Pre-launch hook" placeholder="Runs before the game, a failure stops the launch" value={draft.preLaunchHook} onChange={(v) => update('preLaunchHook', v)} />
+                            <Field label="Post-exit hook" placeholder="Runs after the game exits" value={draft.postExitHook} onChange={(v) => update('postExitHook', v)} />
+                            <Field label="Extra client arguments" placeholder="Appended to the client arguments" value={draft.extraArgs} onChange={(v) => update('extraArgs', v)} />
+                            <div>
+                                <label className="text-xs text-gray-500 mb-1 block">Environment variables, one KEY=value per line</label>
+                                <textarea
+                                    value={envText}
+                                    onChange={(e) => setEnvText(e.target.value)}
+                                    className="w-full h-24 bg-black/40 border border-white/10 rounded-lg p-3 text-xs font-mono text-gray-300 focus:border-[#FFA845]/50 focus:outline-none transition-colors resize-none"
+                                />
+                            </div>
+                        </div>
+                    </Section>
+
+                    {isLinux && (
+                        <Section title="Sandbox" description="Runs the client inside bubblewrap with only the game, its UserData and the JRE visible">
+                            <Toggle label="Sandboxed launch" value={draft.sandbox} onChange={(v) => update('sandbox', v)} />
+                            {draft.sandbox && (
+                                <div className="mt-3">
+                                    <Toggle label="Network access" value={draft.sandboxNetwork} onChange={(v) => update('sandboxNetwork', v)} />
+                                </div>
+                            )}
+                        </Section>
+                    )}
+
+                    <Section title="Auto-restart" description="Starts the game again after it exits, backing off between attempts">
+                        <div className="grid grid-cols-3 gap-2">
+                            {[
+                                ['never', 'Never'],
+                                ['on-crash', 'On crash'],
+                                ['always', 'Always'],
+                            ].map(([value, label]) => (
+                                <button
+                                    key={value}
+                                    onClick={() => update('restartPolicy', value)}
+                                    className={`px-3 py-2 rounded-lg border text-xs transition-colors ${(draft.restartPolicy || 'never') === value ? 'border-[#FFA845]/50 text-[#FFA845] bg-[#FFA845]/10' : 'border-white/10 text-gray-300 bg-black/40 hover:bg-white/5'}`}
+                                >
+                                    {label}
+                                </button>
+                            ))}
+                        </div>
+                        {(draft.restartPolicy || 'never') !== 'never' && (
+                            <div className="grid grid-cols-2 gap-4 mt-3">
+                                <div>
+                                    <label className="text-xs text-gray-500 mb-1 block">Max restarts</label>
+                                    <input
+                                        type="number"
+                                        min={0}
+                                        value={draft.maxRestarts || ''}
+                                        placeholder="3"
+                                        onChange={(e) => update('maxRestarts', Math.max(0, parseInt(e.target.value) || 0))}
+                                        className="w-full bg-black/40 border border-white/10 rounded-lg px-4 py-2 text-sm text-white focus:border-[#FFA845]/50 focus:outline-none transition-colors"
+                                    />
+                                </div>
+                                <div>
+                                    <label className="text-xs text-gray-500 mb-1 block">Within seconds</label>
+                                    <input
+                                        type="number"
+                                        min={0}
+                                        value={draft.restartWindow || ''}
+                                        placeholder="300"
+                                        onChange={(e) => update('restartWindow', Math.max(0, parseInt(e.target.value) || 0))}
+                                        className="w-full bg-black/40 border border-white/10 rounded-lg px-4 py-2 text-sm text-white focus:border-[#FFA845]/50 focus:outline-none transition-colors"
+                                    />
+                                </div>
+                            </div>
+                        )}
+                    </Section>
+
+                    <Section title="User Data" description="Worlds, settings and caches the game keeps for this profile">
+                        <div className="grid grid-cols-2 gap-2 mb-4">
+                            {[
+                                [false, 'Shared'],
+                                [true, 'Isolated'],
+                            ].map(([value, label]) => (
+                                <button
+                                    key={String(value)}
+                                    onClick={() => update('isolatedUserData', value)}
+                                    className={`px-3 py-2 rounded-lg border text-xs transition-colors ${draft.isolatedUserData === value ? 'border-[#FFA845]/50 text-[#FFA845] bg-[#FFA845]/10' : 'border-white/10 text-gray-300 bg-black/40 hover:bg-white/5'}`}
+                                >
+                                    {label}
+                                </button>
+                            ))}
+                        </div>
+                        <p className="text-xs text-gray-500 mb-3">
+                            Bring the shared worlds and settings into this profile's own directory. Moving empties the shared UserData for the other profiles.
+                        </p>
+                        <div className="flex gap-2">
+                            <button
+                                onClick={() => handleMigrate(false)}
+                                disabled={migrating}
+                                className="px-4 py-2 bg-white/5 hover:bg-white/10 border border-white/10 rounded-lg text-sm text-gray-300 hover:text-white transition-colors disabled:opacity-50 flex items-center gap-2"
+                            >
+                                {migrating ? <Loader2 size={16} className="animate-spin" /> : <Copy size={16} />}
+                                Copy shared data
+                            </button>
+                            <button
+                                onClick={() => handleMigrate(true)}
+                                disabled={migrating}
+                                className="px-4 py-2 bg-white/5 hover:bg-white/10 border border-white/10 rounded-lg text-sm text-gray-300 hover:text-white transition-colors disabled:opacity-50 flex items-center gap-2"
+                            >
+                                {migrating ? <Loader2 size={16} className="animate-spin" /> : <MoveRight size={16} />}
+                                Move shared data
+                            </button>
+                        </div>
+                    </Section>
+
+                    {error && <p className="text-xs text-red-400">{error}</p>}
+                </div>
+
+                {/* Footer */}
+                <div className="p-6 border-t border-white/10 bg-black/20 flex justify-end gap-3">
+                    <button
+                        onClick={onClose}
+                        className="px-4 py-2 hover:bg-white/5 text-gray-400 hover:text-white rounded-lg transition-colors text-sm"
+                    >
+                        Cancel
+                    </button>
+                    <button
+                        onClick={handleSave}
+                        disabled={saving || migrating}
+                        className="px-6 py-2 bg-[#FFA845] hover:bg-[#ffb460] text-black font-bold rounded-lg transition-colors text-sm flex items-center gap-2 disabled:opacity-50"
+                    >
+                        {saving ? <Loader2 size={16} className="animate-spin" /> : <Save size={16} />}
+                        {saving ? 'Saving...' : 'Save Changes'}
+                    </button>
+                </div>
+            </motion.div>
+
+            {pendingChange && (
+                <div onClick={(e) => e.stopPropagation()}>
+                    <UUIDChangeModal
+                        change={pendingChange.change}
+                        onConfirm={() => { const updated = pendingChange.profile; setPendingChange(null); save(updated, true); }}
+                        onCancel={() => setPendingChange(null)}
+                    />
+                </div>
+            )}
+        </motion.div>
+    );
+};
+
+// formatEnv lists variables as KEY=value lines, sorted by name
+const formatEnv = (env?: Record<string, string>) =>
+    Object.keys(env || {}).sort().map(key => `${key}=${env![key]}`).join('\n');
+
+// parseEnv reads KEY=value lines, or returns an error message
+const parseEnv = (text: string): Record<string, string> | string => {
+    const env: Record<string, string> = {};
+    for (const raw of text.split('\n')) {
+        const line = raw.trim();
+        if (!line) continue;
+        const eq = line.indexOf('=');
+        if (eq <= 0) return `Invalid environment line "${line}", expected KEY=value`;
+        env[line.slice(0, eq).trim()] = line.slice(eq + 1);
+    }
+    return env;
+};
+
+const Field = ({ label, placeholder, value, onChange }: { label: string; placeholder: string; value: string; onChange: (value: string) => void }) => (
+    <div>
+        <label className="text-xs text-gray-500 mb-1 block">{label}</label>
+        <input
+            type="text"
+            value={value || ''}
+            placeholder={placeholder}
+            onChange={(e) => onChange(e.target.value)}
+            className="w-full bg-black/40 border border-white/10 rounded-lg px-4 py-2 text-sm font-mono text-white focus:outline-none focus:border-[#FFA845]/50"
+        />
+    </div>
+);
+
+const Toggle = ({ label, value, onChange }: { label: string; value: boolean; onChange: (value: boolean) => void }) => (
+    <div className="flex items-center justify-between">
+        <span className="text-sm text-white">{label}</span>
+        <button
+            onClick={() => onChange(!value)}
+            className={`w-12 h-6 rounded-full transition-colors relative ${value ? 'bg-[#FFA845]' : 'bg-gray-700'}`}
+        >
+            <div className={`absolute top-1 w-4 h-4 rounded-full bg-white transition-all ${value ? 'left-7' : 'left-1'}`} />
+        </button>
+    </div>
+);
+
+const Section = ({ title, description, children }: { title: string; description: string; children: React.ReactNode }) => (
+    <div className="bg-white/5 border border-white/5 rounded-xl p-5">
+        <div className="mb-4">
+            <h4 className="text-sm font-bold text-white mb-1">{title}</h4>
+            <p className="text-xs text-gray-500">{description}</p>
+        </div>
+        {children}
+    </div>
+);
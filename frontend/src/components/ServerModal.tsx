@@ -0,0 +1,257 @@
+import React, { useState, useEffect, useRef } from 'react';
+import { motion } from 'framer-motion';
+import { Server, X, Play, Square, Send, Save, Loader2 } from 'lucide-react';
+import { GetServerSettings, SaveServerSettings, StartServer, StopServer, SendServerCommand, ListRunning } from '../../wailsjs/go/app/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
+import { config, instance } from '../../wailsjs/go/models';
+
+interface ServerModalProps {
+  onClose: () => void;
+}
+
+// Lines of console output kept in the modal
+const maxConsoleLines = 500;
+
+export const ServerModal: React.FC<ServerModalProps> = ({ onClose }) => {
+  const [settings, setSettings] = useState<config.ServerSettings | null>(null);
+  const [server, setServer] = useState<instance.Info | null>(null);
+  const [lines, setLines] = useState<string[]>([]);
+  const [command, setCommand] = useState('');
+  const [busy, setBusy] = useState(false);
+  const [saving, setSaving] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+  const consoleRef = useRef<HTMLDivElement>(null);
+
+  useEffect(() => {
+    GetServerSettings().then(setSettings).catch((err) => console.error("Failed to load server settings:", err));
+    ListRunning()
+      .then((running) => setServer(running.find((i) => i.kind === 'server') || null))
+      .catch((err) => console.error("Failed to list running servers:", err));
+
+    const logListener = EventsOn('server-log', (line: any) => {
+      setLines((prev) => [...prev, line.line].slice(-maxConsoleLines));
+    });
+    const startedListener = EventsOn('server-started', (event: any) => {
+      setServer(event.instance);
+      setLines([]);
+    });
+    const stoppedListener = EventsOn('server-stopped', (event: any) => {
+      setServer(null);
+      setBusy(false);
+      const exit = event.exit;
+      setLines((prev) => [...prev, exit && exit.exitCode !== 0 ? `Server exited with code ${exit.exitCode}` : 'Server stopped']);
+    });
+
+    return () => {
+      logListener();
+      startedListener();
+      stoppedListener();
+    };
+  }, []);
+
+  useEffect(() => {
+    const el = consoleRef.current;
+    if (el) el.scrollTop = el.scrollHeight;
+  }, [lines]);
+
+  const describe = (err: any) => err?.message || String(err);
+
+  const handleStart = async () => {
+    setBusy(true);
+    setError(null);
+    try {
+      setServer(await StartServer());
+    } catch (err) {
+      setError(describe(err));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleStop = async () => {
+    if (!server) return;
+    setBusy(true);
+    setError(null);
+    try {
+      // Busy until the server-stopped event arrives
+      await StopServer(server.id);
+    } catch (err) {
+      setError(describe(err));
+      setBusy(false);
+    }
+  };
+
+  const handleCommand = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (!server || !command.trim()) return;
+    try {
+      await SendServerCommand(server.id, command);
+      setLines((prev) => [...prev, `> ${command}`].slice(-maxConsoleLines));
+      setCommand('');
+    } catch (err) {
+      setError(describe(err));
+    }
+  };
+
+  const handleSave = async () => {
+    if (!settings) return;
+    setSaving(true);
+    setError(null);
+    try {
+      await SaveServerSettings(settings);
+    } catch (err) {
+      setError(describe(err));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const updateSetting = (key: keyof config.ServerSettings, value: any) => {
+    setSettings((prev) => prev ? config.ServerSettings.createFrom({ ...prev, [key]: value }) : prev);
+  };
+
+  const inputClass = "w-full bg-black/40 border border-white/10 rounded-lg px-3 py-2 text-sm text-white focus:border-[#FFA845]/50 focus:outline-none transition-colors disabled:opacity-50";
+
+  return (
+    <motion.div
+      initial={{ opacity: 0 }}
+      animate={{ opacity: 1 }}
+      exit={{ opacity: 0 }}
+      className="fixed inset-0 bg-black/80 backdrop-blur-sm flex items-center justify-center z-50 p-4"
+      onClick={onClose}
+    >
+      <motion.div
+        initial={{ scale: 0.9, opacity: 0 }}
+        animate={{ scale: 1, opacity: 1 }}
+        exit={{ scale: 0.9, opacity: 0 }}
+        onClick={(e) => e.stopPropagation()}
+        className="w-full max-w-2xl bg-[#090909]/95 backdrop-blur-xl rounded-2xl border border-[#FFA845]/20 overflow-hidden shadow-2xl max-h-[90vh] flex flex-col"
+      >
+        {/* Header */}
+        <div className="p-6 border-b border-white/10 bg-gradient-to-r from-[#FFA845]/10 to-transparent">
+          <div className="flex items-start justify-between">
+            <div className="flex items-start gap-3">
+              <div className="p-2 rounded-lg bg-[#FFA845]/20">
+                <Server size={24} className="text-[#FFA845]" />
+              </div>
+              <div>
+                <h3 className="text-lg font-bold text-white">Local Server</h3>
+                <p className="text-xs text-gray-400 mt-1">
+                  {server ? `Running on port ${settings?.port ?? ''} (PID ${server.pid})` : 'Run HytaleServer.jar from the selected installation'}
+                </p>
+              </div>
+            </div>
+            <button
+              onClick={onClose}
+              className="p-1 hover:bg-white/10 rounded-lg transition-colors"
+            >
+              <X size={20} className="text-gray-400" />
+            </button>
+          </div>
+        </div>
+
+        {/* Content */}
+        <div className="flex-1 overflow-y-auto p-6 space-y-4">
+          {settings && (
+            <div className="grid grid-cols-3 gap-3">
+              <div>
+                <label className="text-xs text-gray-500 mb-1 block">Min memory (GB)</label>
+                <input
+                  type="number"
+                  min={0}
+                  value={settings.minMemory}
+                  disabled={!!server}
+                  onChange={(e) => updateSetting('minMemory', Math.max(0, parseInt(e.target.value) || 0))}
+                  className={inputClass}
+                />
+              </div>
+              <div>
+                <label className="text-xs text-gray-500 mb-1 block">Max memory (GB)</label>
+                <input
+                  type="number"
+                  min={0}
+                  value={settings.maxMemory}
+                  disabled={!!server}
+                  onChange={(e) => updateSetting('maxMemory', Math.max(0, parseInt(e.target.value) || 0))}
+                  className={inputClass}
+                />
+              </div>
+              <div>
+                <label className="text-xs text-gray-500 mb-1 block">Port</label>
+                <input
+                  type="number"
+                  min={1}
+                  max={65535}
+                  value={settings.port}
+                  disabled={!!server}
+                  onChange={(e) => updateSetting('port', parseInt(e.target.value) || 0)}
+                  className={inputClass}
+                />
+              </div>
+            </div>
+          )}
+
+          <div
+            ref={consoleRef}
+            className="h-64 overflow-y-auto bg-black/60 border border-white/10 rounded-lg p-3 font-mono text-[11px] text-gray-300 whitespace-pre-wrap select-text"
+          >
+            {lines.length === 0
+              ? <span className="text-gray-600">{server ? 'Waiting for output...' : 'Server is not running'}</span>
+              : lines.map((line, i) => <div key={i}>{line}</div>)}
+          </div>
+
+          <form onSubmit={handleCommand} className="flex gap-2">
+            <input
+              type="text"
+              value={command}
+              disabled={!server}
+              onChange={(e) => setCommand(e.target.value)}
+              placeholder="Server command"
+              className={`${inputClass} flex-1 font-mono`}
+            />
+            <button
+              type="submit"
+              disabled={!server || !command.trim()}
+              className="px-4 py-2 bg-white/5 hover:bg-white/10 border border-white/10 rounded-lg text-gray-300 hover:text-white transition-colors disabled:opacity-50"
+            >
+              <Send size={16} />
+            </button>
+          </form>
+
+          {error && <p className="text-xs text-red-400">{error}</p>}
+        </div>
+
+        {/* Footer */}
+        <div className="p-6 border-t border-white/10 flex justify-end gap-3">
+          <button
+            onClick={handleSave}
+            disabled={saving || !settings || !!server}
+            className="px-4 py-2 bg-white/5 hover:bg-white/10 border border-white/10 rounded-lg text-sm text-gray-300 hover:text-white transition-colors disabled:opacity-50 flex items-center gap-2"
+          >
+            {saving ? <Loader2 size={16} className="animate-spin" /> : <Save size={16} />}
+            Save
+          </button>
+          {server ? (
+            <button
+              onClick={handleStop}
+              disabled={busy}
+              className="px-6 py-2 bg-red-500/20 hover:bg-red-500/30 border border-red-500/30 rounded-lg text-sm font-medium text-white transition-colors disabled:opacity-50 flex items-center gap-2"
+            >
+              {busy ? <Loader2 size={16} className="animate-spin" /> : <Square size={16} />}
+              Stop
+            </button>
+          ) : (
+            <button
+              onClick={handleStart}
+              disabled={busy}
+              className="px-6 py-2 bg-[#FFA845]/20 hover:bg-[#FFA845]/30 border border-[#FFA845]/40 rounded-lg text-sm font-medium text-white transition-colors disabled:opacity-50 flex items-center gap-2"
+            >
+              {busy ? <Loader2 size={16} className="animate-spin" /> : <Play size={16} />}
+              Start
+            </button>
+          )}
+        </div>
+      </motion.div>
+    </motion.div>
+  );
+};
@@ -1,8 +1,8 @@
 import React, { useState, useEffect } from 'react';
 import { motion } from 'framer-motion';
-import { Settings, X, Save, HardDrive, Monitor, Cpu, Folder, Loader2, ChevronDown } from 'lucide-react';
-import { GetSettings, SaveSettings, GetVersions } from '../../wailsjs/go/app/App';
-import { config, app } from '../../wailsjs/go/models';
+import { Settings, X, Save, HardDrive, Monitor, Cpu, Folder, Loader2, ChevronDown, Trash2, Wrench } from 'lucide-react';
+import { GetSettings, SaveSettings, GetVersions, SelectGameDir, MoveInstallation, ListInstalledVersions, DeleteVersion, VerifyVersion, GetPatchCache, ClearPatchCache, GetButlerSettings, SaveButlerSettings, ImportButler } from '../../wailsjs/go/app/App';
+import { config, app, game, patch } from '../../wailsjs/go/models';
 import { AnimatePresence } from 'framer-motion';
 
 interface SettingsModalProps {
@@ -13,18 +13,29 @@ export const SettingsModal: React.FC<SettingsModalProps> = ({ onClose }) => {
     const [activeTab, setActiveTab] = useState<'game' | 'java' | 'video'>('game');
     const [settings, setSettings] = useState<config.GameSettings | null>(null);
     const [availableVersions, setAvailableVersions] = useState<number[]>([]);
+    const [installedVersions, setInstalledVersions] = useState<game.InstalledVersion[]>([]);
+    const [verifying, setVerifying] = useState<string | null>(null);
+    const [verifyResult, setVerifyResult] = useState<game.VerifyResult | null>(null);
+    const [patchCache, setPatchCache] = useState<patch.CacheInfo | null>(null);
+    const [butler, setButler] = useState<config.ButlerSettings | null>(null);
+    const [importingButler, setImportingButler] = useState(false);
+    const [butlerVersion, setButlerVersion] = useState<string | null>(null);
     const [loading, setLoading] = useState(true);
     const [saving, setSaving] = useState(false);
+    const [moving, setMoving] = useState(false);
     const [isVersionOpen, setIsVersionOpen] = useState(false);
     const [isChannelOpen, setIsChannelOpen] = useState(false);
 
     useEffect(() => {
         loadSettings();
+        loadPatchCache();
+        loadButler();
     }, []);
 
     useEffect(() => {
         if (settings?.channel) {
             loadVersions(settings.channel);
+            loadInstalledVersions(settings.channel);
         }
     }, [settings?.channel]);
 
@@ -43,6 +54,97 @@ export const SettingsModal: React.FC<SettingsModalProps> = ({ onClose }) => {
         }
     };
 
+    const loadInstalledVersions = async (channel: string) => {
+        try {
+            setInstalledVersions(await ListInstalledVersions(channel) || []);
+        } catch (err) {
+            console.error("Failed to load installed versions:", err);
+            setInstalledVersions([]);
+        }
+    };
+
+    const handleDeleteVersion = async (v: game.InstalledVersion) => {
+        try {
+            await DeleteVersion(v.channel, v.dir);
+        } catch (err) {
+            console.error("Failed to delete version:", err);
+        }
+        loadInstalledVersions(v.channel);
+    };
+
+    const handleVerifyVersion = async (v: game.InstalledVersion) => {
+        setVerifying(v.dir);
+        setVerifyResult(null);
+        try {
+            setVerifyResult(await VerifyVersion(v.channel, v.dir, true, []));
+        } catch (err) {
+            console.error("Failed to verify version:", err);
+        } finally {
+            setVerifying(null);
+        }
+        loadInstalledVersions(v.channel);
+    };
+
+    // Extra files are only removed once the user saw the list
+    const handleRemoveExtra = async (result: game.VerifyResult) => {
+        setVerifying(result.dir);
+        try {
+            setVerifyResult(await VerifyVersion(result.channel, result.dir, true, result.extra || []));
+        } catch (err) {
+            console.error("Failed to remove extra files:", err);
+        } finally {
+            setVerifying(null);
+        }
+        loadInstalledVersions(result.channel);
+    };
+
+    const loadPatchCache = async () => {
+        try {
+            setPatchCache(await GetPatchCache());
+        } catch (err) {
+            console.error("Failed to load patch cache:", err);
+        }
+    };
+
+    const handleClearPatchCache = async () => {
+        try {
+            await ClearPatchCache();
+        } catch (err) {
+            console.error("Failed to clear patch cache:", err);
+        }
+        loadPatchCache();
+    };
+
+    const loadButler = async () => {
+        try {
+            setButler(await GetButlerSettings());
+        } catch (err) {
+            console.error("Failed to load butler settings:", err);
+        }
+    };
+
+    const handleImportButler = async () => {
+        setImportingButler(true);
+        try {
+            const version = await ImportButler();
+            if (version) setButlerVersion(version);
+        } catch (err) {
+            console.error("Failed to import butler:", err);
+        } finally {
+            setImportingButler(false);
+            loadButler();
+        }
+    };
+
+    const handleToggleNative = async (current: config.ButlerSettings) => {
+        try {
+            await SaveButlerSettings(config.ButlerSettings.createFrom({ ...current, native: !current.native }));
+        } catch (err) {
+            console.error("Failed to save butler settings:", err);
+        }
+        loadButler();
+    };
+
     const loadSettings = async () => {
         try {
             const data = await GetSettings();
@@ -66,6 +168,21 @@ export const SettingsModal: React.FC<SettingsModalProps> = ({ onClose }) => {
         }
     };
 
+    const handleMoveInstallation = async () => {
+        if (!settings) return;
+        try {
+            const dir = await SelectGameDir();
+            if (!dir) return;
+            setMoving(true);
+            await MoveInstallation(dir);
+            updateSetting('gameDir', dir);
+        } catch (err) {
+            console.error("Failed to move installation:", err);
+        } finally {
+            setMoving(false);
+        }
+    };
+
     const updateSetting = (key: keyof config.GameSettings, value: any) => {
         if (!settings) return;
         setSettings(prev => {
@@ -144,8 +261,13 @@ export const SettingsModal: React.FC<SettingsModalProps> = ({ onClose }) => {
                                                 disabled
                                                 className="flex-1 bg-black/40 border border-white/10 rounded-lg px-4 py-2 text-sm text-gray-400 cursor-not-allowed"
                                             />
-                                            <button className="px-4 py-2 bg-white/5 hover:bg-white/10 border border-white/10 rounded-lg text-gray-300 transition-colors">
-                                                <Folder size={18} />
+                                            <button
+                                                onClick={handleMoveInstallation}
+                                                disabled={moving}
+                                                title="Move installation"
+                                                className="px-4 py-2 bg-white/5 hover:bg-white/10 border border-white/10 rounded-lg text-gray-300 transition-colors disabled:opacity-50"
+                                            >
+                                                {moving ? <Loader2 size={18} className="animate-spin" /> : <Folder size={18} />}
                                             </button>
                                         </div>
                                     </Section>
@@ -235,6 +357,146 @@ export const SettingsModal: React.FC<SettingsModalProps> = ({ onClose }) => {
                                             </div>
                                         </div>
                                     </Section>
+                                    <Section title="Installed Versions" description="Builds kept side by side on this channel">
+                                        <div className="space-y-2">
+                                            {installedVersions.length === 0 && (
+                                                <p className="text-xs text-gray-500">No versions installed</p>
+                                            )}
+                                            {installedVersions.map(v => {
+                                                const selected = (v.dir === 'latest' ? 0 : Number(v.dir)) === settings.gameVersion;
+                                                return (
+                                                    <div key={v.dir} className="flex items-center justify-between bg-black/40 border border-white/10 rounded-lg px-4 py-2">
+                                                        <div
+                                                            onClick={() => updateSetting('gameVersion', v.dir === 'latest' ? 0 : Number(v.dir))}
+                                                            className="flex-1 cursor-pointer"
+                                                        >
+                                                            <span className={`text-sm ${selected ? 'text-[#FFA845] font-bold' : 'text-white'}`}>
+                                                                {v.dir === 'latest' ? `Latest (Version ${v.version})` : `Version ${v.version}`}
+                                                            </span>
+                                                            <span className="text-xs text-gray-500 ml-2">{(v.size / 1024 / 1024 / 1024).toFixed(2)} GB</span>
+                                                        </div>
+                                                        <button
+                                                            onClick={() => handleVerifyVersion(v)}
+                                                            disabled={verifying !== null}
+                                                            title="Verify & Repair"
+                                                            className="p-1 text-gray-500 hover:text-[#FFA845] transition-colors disabled:opacity-50"
+                                                        >
+                                                            {verifying === v.dir ? <Loader2 size={16} className="animate-spin" /> : <Wrench size={16} />}
+                                                        </button>
+                                                        <button
+                                                            onClick={() => handleDeleteVersion(v)}
+                                                            title="Delete version"
+                                                            className="p-1 text-gray-500 hover:text-red-400 transition-colors"
+                                                        >
+                                                            <Trash2 size={16} />
+                                                        </button>
+                                                    </div>
+                                                );
+                                            })}
+                                            {verifyResult && (
+                                                <p className="text-xs text-gray-500">
+                                                    {`Version ${verifyResult.version}: ${verifyResult.checked} files checked, `
+                                                        + `${verifyResult.missing?.length || 0} missing, ${verifyResult.changed?.length || 0} changed, ${verifyResult.extra?.length || 0} extra`
+                                                        + (verifyResult.repaired ? ' (repaired)' : '')
+                                                        + (verifyResult.removed?.length ? `, ${verifyResult.removed.length} extra removed` : '')}
+                                                </p>
+                                            )}
+                                            {verifyResult && !verifyResult.removed?.length && (verifyResult.extra?.length || 0) > 0 && (
+                                                <div className="bg-black/40 border border-white/10 rounded-lg p-3 space-y-2">
+                                                    <p className="text-xs text-gray-400">
+                                                        These files are not part of the build. They may be mods or settings, check them before removing.
+                                                    </p>
+                                                    <div className="max-h-32 overflow-y-auto font-mono text-[11px] text-gray-500 select-text">
+                                                        {verifyResult.extra.map(rel => <div key={rel}>{rel}</div>)}
+                                                    </div>
+                                                    <button
+                                                        onClick={() => handleRemoveExtra(verifyResult)}
+                                                        disabled={verifying !== null}
+                                                        className="px-3 py-1.5 bg-red-500/20 hover:bg-red-500/30 border border-red-500/30 rounded-lg text-xs text-white transition-colors disabled:opacity-50 flex items-center gap-2"
+                                                    >
+                                                        <Trash2 size={14} />
+                                                        {`Remove ${verifyResult.extra.length} extra files`}
+                                                    </button>
+                                                </div>
+                                            )}
+                                        </div>
+                                    </Section>
+                                    <Section title="Patch Cache" description="Downloaded patches kept for reinstalls and updates">
+                                        <div className="flex gap-2 items-center">
+                                            <div className="flex-1 bg-black/40 border border-white/10 rounded-lg px-4 py-2 text-sm text-gray-400">
+                                                {patchCache
+                                                    ? `${patchCache.entries.length} patches, ${(patchCache.totalSize / 1024 / 1024).toFixed(0)} MB`
+                                                    : 'Empty'}
+                                            </div>
+                                            <input
+                                                type="number"
+                                                min={0}
+                                                value={settings.patchCacheLimit}
+                                                onChange={(e) => updateSetting('patchCacheLimit', Math.max(0, parseInt(e.target.value) || 0))}
+                                                title="Cache limit in MB"
+                                                className="w-28 bg-black/40 border border-white/10 rounded-lg px-4 py-2 text-sm text-white focus:border-[#FFA845]/50 focus:outline-none transition-colors"
+                                            />
+                                            <span className="text-xs text-gray-500">MB</span>
+                                            <button
+                                                onClick={handleClearPatchCache}
+                                                title="Clear patch cache"
+                                                className="px-4 py-2 bg-white/5 hover:bg-white/10 border border-white/10 rounded-lg text-gray-300 hover:text-red-400 transition-colors"
+                                            >
+                                                <Trash2 size={18} />
+                                            </button>
+                                        </div>
+                                    </Section>
+                                    <Section title="Butler" description="Patch tool, downloaded at a pinned version or imported from a file">
+                                        <div className="flex gap-2">
+                                            <div className="flex-1 bg-black/40 border border-white/10 rounded-lg px-4 py-2 text-sm text-gray-400 truncate">
+                                                {butlerVersion
+                                                    ? `Installed ${butlerVersion}`
+                                                    : butler?.path
+                                                        ? butler.path
+                                                        : `Version ${butler?.version || 'unknown'}`}
+                                            </div>
+                                            <button
+                                                onClick={handleImportButler}
+                                                disabled={importingButler}
+                                                className="px-4 py-2 bg-white/5 hover:bg-white/10 border border-white/10 rounded-lg text-sm text-gray-300 hover:text-white transition-colors disabled:opacity-50 flex items-center gap-2"
+                                            >
+                                                {importingButler ? <Loader2 size={18} className="animate-spin" /> : <Folder size={18} />}
+                                                Import
+                                            </button>
+                                        </div>
+                                        {butler && (
+                                            <div className="flex items-center justify-between mt-3">
+                                                <div>
+                                                    <h4 className="text-sm font-medium text-white">Built-in patcher</h4>
+                                                    <p className="text-xs text-gray-500">Experimental, applies patches without butler and falls back to it on failure</p>
+                                                </div>
+                                                <button
+                                                    onClick={() => handleToggleNative(butler)}
+                                                    className={`w-12 h-6 rounded-full transition-colors relative ${butler.native ? 'bg-[#FFA845]' : 'bg-gray-700'}`}
+                                                >
+                                                    <div className={`absolute top-1 w-4 h-4 rounded-full bg-white transition-all ${butler.native ? 'left-7' : 'left-1'}`} />
+                                                </button>
+                                            </div>
+                                        )}
+                                    </Section>
+                                    <Section title="Launcher Window" description="What the launcher does while the game is running">
+                                        <div className="grid grid-cols-4 gap-2">
+                                            {[
+                                                ['keep', 'Keep open'],
+                                                ['hide', 'Hide'],
+                                                ['minimize', 'Minimize'],
+                                                ['close', 'Close'],
+                                            ].map(([value, label]) => (
+                                                <button
+                                                    key={value}
+                                                    onClick={() => updateSetting('launcherBehavior', value)}
+                                                    className={`px-3 py-2 rounded-lg border text-xs transition-colors ${(settings.launcherBehavior || 'keep') === value ? 'border-[#FFA845]/50 text-[#FFA845] bg-[#FFA845]/10' : 'border-white/10 text-gray-300 bg-black/40 hover:bg-white/5'}`}
+                                                >
+                                                    {label}
+                                                </button>
+                                            ))}
+                                        </div>
+                                    </Section>
                                     <div className="flex items-center justify-between bg-white/5 p-4 rounded-lg border border-white/5">
                                         <div>
                                             <h4 className="text-sm font-medium text-white">Online Fix</h4>
@@ -247,19 +509,44 @@ export const SettingsModal: React.FC<SettingsModalProps> = ({ onClose }) => {
                                             <div className={`absolute top-1 w-4 h-4 rounded-full bg-white transition-all ${settings.onlineFix ? 'left-7' : 'left-1'}`} />
                                         </button>
                                     </div>
+                                    <div className="bg-white/5 p-4 rounded-lg border border-white/5 space-y-3">
+                                        <div className="flex items-center justify-between">
+                                            <div>
+                                                <h4 className="text-sm font-medium text-white">Discord Rich Presence</h4>
+                                                <p className="text-xs text-gray-500">Shows the running profile on your Discord status</p>
+                                            </div>
+                                            <button
+                                                onClick={() => updateSetting('discordPresence', !settings.discordPresence)}
+                                                className={`w-12 h-6 rounded-full transition-colors relative ${settings.discordPresence ? 'bg-[#FFA845]' : 'bg-gray-700'}`}
+                                            >
+                                                <div className={`absolute top-1 w-4 h-4 rounded-full bg-white transition-all ${settings.discordPresence ? 'left-7' : 'left-1'}`} />
+                                            </button>
+                                        </div>
+                                        {settings.discordPresence && (
+                                            <input
+                                                type="text"
+                                                value={settings.discordClientId || ''}
+                                                onChange={(e) => updateSetting('discordClientId', e.target.value.trim())}
+                                                placeholder="Discord application ID"
+                                                className="w-full bg-black/40 border border-white/10 rounded-lg px-4 py-2 text-sm text-white focus:outline-none focus:border-[#FFA845]/50"
+                                            />
+                                        )}
+                                    </div>
                                 </div>
                             )}
 
                             {activeTab === 'video' && (
                                 <div className="space-y-6">
-                                    <Section title="Resolution" description="Set the game window dimensions">
+                                    <Section title="Resolution" description="Set the game window dimensions, leave empty to keep the game's own size">
                                         <div className="grid grid-cols-2 gap-4">
                                             <div>
                                                 <label className="text-xs text-gray-500 mb-1 block">Width</label>
                                                 <input
                                                     type="number"
-                                                    value={settings.width}
-                                                    onChange={(e) => updateSetting('width', parseInt(e.target.value))}
+                                                    min={0}
+                                                    value={settings.width || ''}
+                                                    placeholder="Game default"
+                                                    onChange={(e) => updateSetting('width', Math.max(0, parseInt(e.target.value) || 0))}
                                                     className="w-full bg-black/40 border border-white/10 rounded-lg px-4 py-2 text-sm text-white focus:border-[#FFA845]/50 focus:outline-none transition-colors"
                                                 />
                                             </div>
@@ -267,8 +554,10 @@ export const SettingsModal: React.FC<SettingsModalProps> = ({ onClose }) => {
                                                 <label className="text-xs text-gray-500 mb-1 block">Height</label>
                                                 <input
                                                     type="number"
-                                                    value={settings.height}
-                                                    onChange={(e) => updateSetting('height', parseInt(e.target.value))}
+                                                    min={0}
+                                                    value={settings.height || ''}
+                                                    placeholder="Game default"
+                                                    onChange={(e) => updateSetting('height', Math.max(0, parseInt(e.target.value) || 0))}
                                                     className="w-full bg-black/40 border border-white/10 rounded-lg px-4 py-2 text-sm text-white focus:border-[#FFA845]/50 focus:outline-none transition-colors"
                                                 />
                                             </div>
@@ -0,0 +1,74 @@
+import React from 'react';
+import { motion, AnimatePresence } from 'framer-motion';
+import { game } from '../../wailsjs/go/models';
+
+interface UUIDChangeModalProps {
+  change: game.UUIDChange;
+  onConfirm: () => void;
+  onCancel: () => void;
+}
+
+export const UUIDChangeModal: React.FC<UUIDChangeModalProps> = ({
+  change,
+  onConfirm,
+  onCancel,
+}) => {
+  const worlds = change.worlds || [];
+
+  return (
+    <AnimatePresence>
+      <motion.div
+        initial={{ opacity: 0 }}
+        animate={{ opacity: 1 }}
+        exit={{ opacity: 0 }}
+        className="fixed inset-0 bg-black/70 backdrop-blur-sm z-50 flex items-center justify-center p-4"
+      >
+        <motion.div
+          initial={{ scale: 0.85, y: 20, opacity: 0 }}
+          animate={{ scale: 1, y: 0, opacity: 1 }}
+          exit={{ scale: 0.85, y: 20, opacity: 0 }}
+          transition={{ type: "spring", damping: 20, stiffness: 300 }}
+          className="bg-[#0f0f0f] border border-[#FFA845]/20 rounded-2xl p-8 max-w-md w-full shadow-2xl"
+        >
+          <h2 className="text-2xl font-bold text-white mb-4">Change player identity?</h2>
+
+          <p className="text-gray-300 mb-4 leading-relaxed">
+            This change gives the player a new UUID. Servers and worlds know players by UUID,
+            so existing progress will not carry over.
+          </p>
+
+          <div className="bg-black/40 border border-white/10 rounded-lg p-3 mb-4 font-mono text-[11px] text-gray-400 space-y-1">
+            <div>Old: <span className="text-gray-200">{change.previous}</span></div>
+            <div>New: <span className="text-gray-200">{change.next}</span></div>
+          </div>
+
+          {worlds.length > 0 && (
+            <div className="mb-6">
+              <p className="text-sm text-red-400 mb-2">
+                {worlds.length === 1 ? '1 world' : `${worlds.length} worlds`} may no longer recognize the player:
+              </p>
+              <ul className="max-h-32 overflow-y-auto text-sm text-gray-300 list-disc pl-5 space-y-1">
+                {worlds.map((world) => <li key={world}>{world}</li>)}
+              </ul>
+            </div>
+          )}
+
+          <div className="flex gap-4 justify-end">
+            <button
+              onClick={onCancel}
+              className="px-6 py-3 bg-[#1a1a1a] hover:bg-[#222] text-gray-300 rounded-lg transition-colors border border-white/10"
+            >
+              Cancel
+            </button>
+            <button
+              onClick={onConfirm}
+              className="px-6 py-3 bg-red-600 hover:bg-red-700 text-white font-medium rounded-lg transition-colors shadow-lg shadow-red-900/30"
+            >
+              Change UUID
+            </button>
+          </div>
+        </motion.div>
+      </motion.div>
+    </AnimatePresence>
+  );
+};
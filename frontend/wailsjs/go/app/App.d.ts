@@ -1,24 +1,39 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {config} from '../models';
+import {game} from '../models';
 import {updater} from '../models';
 import {diagnostics} from '../models';
 import {app} from '../models';
+import {patch} from '../models';
+import {instance} from '../models';
 
 export function AddProfile(arg1:string):Promise<config.Profile>;
 
+export function CheckProfileUUID(arg1:config.Profile):Promise<game.UUIDChange>;
+
 export function CheckUpdate():Promise<updater.Asset>;
 
+export function ClearPatchCache():Promise<void>;
+
 export function DeleteGame():Promise<void>;
 
-export function DeleteProfile(arg1:string):Promise<void>;
+export function DeleteProfile(arg1:string,arg2:boolean):Promise<void>;
+
+export function DeleteVersion(arg1:string,arg2:string):Promise<void>;
 
 export function DownloadAndLaunch(arg1:string):Promise<void>;
 
+export function GetButlerSettings():Promise<config.ButlerSettings>;
+
 export function GetCrashReports():Promise<Array<diagnostics.CrashReport>>;
 
 export function GetCurrentProfile():Promise<config.Profile>;
 
+export function GetGameLog(arg1:string):Promise<string>;
+
+export function GetGraphicsSettings():Promise<config.GraphicsSettings>;
+
 export function GetLauncherVersion():Promise<string>;
 
 export function GetLogs():Promise<string>;
@@ -27,26 +42,68 @@ export function GetNews():Promise<Array<app.BlogPost>>;
 
 export function GetNick():Promise<string>;
 
+export function GetPatchCache():Promise<patch.CacheInfo>;
+
 export function GetProfiles():Promise<Array<config.Profile>>;
 
+export function GetServerSettings():Promise<config.ServerSettings>;
+
 export function GetSettings():Promise<config.GameSettings>;
 
 export function GetVersions(arg1:string):Promise<app.GameVersions>;
 
+export function ImportButler():Promise<string>;
+
+export function Kill(arg1:string):Promise<void>;
+
+export function ListGameLogs():Promise<Array<game.GameLogInfo>>;
+
+export function ListInstalledVersions(arg1:string):Promise<Array<game.InstalledVersion>>;
+
+export function ListRunning():Promise<Array<instance.Info>>;
+
+export function MigrateUserData(arg1:string,arg2:boolean):Promise<void>;
+
+export function MoveInstallation(arg1:string):Promise<void>;
+
 export function OpenFolder():Promise<void>;
 
+export function PreviewLaunch(arg1:string):Promise<game.LaunchPreview>;
+
 export function RunDiagnostics():Promise<app.DiagnosticReport>;
 
+export function SaveButlerSettings(arg1:config.ButlerSettings):Promise<void>;
+
 export function SaveDiagnosticReport():Promise<string>;
 
+export function SaveGraphicsSettings(arg1:config.GraphicsSettings):Promise<void>;
+
+export function SaveProfile(arg1:config.Profile,arg2:boolean):Promise<void>;
+
+export function SaveServerSettings(arg1:config.ServerSettings):Promise<void>;
+
 export function SaveSettings(arg1:config.GameSettings):Promise<void>;
 
+export function SelectGameDir():Promise<string>;
+
+export function SendServerCommand(arg1:string,arg2:string):Promise<void>;
+
+export function SetActiveVersion(arg1:string,arg2:string):Promise<void>;
+
 export function SetCurrentProfile(arg1:string):Promise<void>;
 
 export function SetNick(arg1:string):Promise<void>;
 
+export function StartServer():Promise<instance.Info>;
+
+export function Stop(arg1:string):Promise<void>;
+
 export function StopGame():Promise<void>;
 
+export function StopServer(arg1:string):Promise<void>;
+
 export function Update():Promise<void>;
 
-export function UpdateProfile(arg1:string,arg2:string):Promise<void>;
+export function UpdateProfile(arg1:string,arg2:string,arg3:boolean):Promise<void>;
+
+export function VerifyVersion(arg1:string,arg2:string,arg3:boolean,arg4:Array<string>):Promise<game.VerifyResult>;
@@ -6,22 +6,38 @@ export function AddProfile(arg1) {
   return window['go']['app']['App']['AddProfile'](arg1);
 }
 
+export function CheckProfileUUID(arg1) {
+  return window['go']['app']['App']['CheckProfileUUID'](arg1);
+}
+
 export function CheckUpdate() {
   return window['go']['app']['App']['CheckUpdate']();
 }
 
+export function ClearPatchCache() {
+  return window['go']['app']['App']['ClearPatchCache']();
+}
+
 export function DeleteGame() {
   return window['go']['app']['App']['DeleteGame']();
 }
 
-export function DeleteProfile(arg1) {
-  return window['go']['app']['App']['DeleteProfile'](arg1);
+export function DeleteProfile(arg1, arg2) {
+  return window['go']['app']['App']['DeleteProfile'](arg1, arg2);
+}
+
+export function DeleteVersion(arg1, arg2) {
+  return window['go']['app']['App']['DeleteVersion'](arg1, arg2);
 }
 
 export function DownloadAndLaunch(arg1) {
   return window['go']['app']['App']['DownloadAndLaunch'](arg1);
 }
 
+export function GetButlerSettings() {
+  return window['go']['app']['App']['GetButlerSettings']();
+}
+
 export function GetCrashReports() {
   return window['go']['app']['App']['GetCrashReports']();
 }
@@ -30,6 +46,14 @@ export function GetCurrentProfile() {
   return window['go']['app']['App']['GetCurrentProfile']();
 }
 
+export function GetGameLog(arg1) {
+  return window['go']['app']['App']['GetGameLog'](arg1);
+}
+
+export function GetGraphicsSettings() {
+  return window['go']['app']['App']['GetGraphicsSettings']();
+}
+
 export function GetLauncherVersion() {
   return window['go']['app']['App']['GetLauncherVersion']();
 }
@@ -46,10 +70,18 @@ export function GetNick() {
   return window['go']['app']['App']['GetNick']();
 }
 
+export function GetPatchCache() {
+  return window['go']['app']['App']['GetPatchCache']();
+}
+
 export function GetProfiles() {
   return window['go']['app']['App']['GetProfiles']();
 }
 
+export function GetServerSettings() {
+  return window['go']['app']['App']['GetServerSettings']();
+}
+
 export function GetSettings() {
   return window['go']['app']['App']['GetSettings']();
 }
@@ -58,22 +90,82 @@ export function GetVersions(arg1) {
   return window['go']['app']['App']['GetVersions'](arg1);
 }
 
+export function ImportButler() {
+  return window['go']['app']['App']['ImportButler']();
+}
+
+export function Kill(arg1) {
+  return window['go']['app']['App']['Kill'](arg1);
+}
+
+export function ListGameLogs() {
+  return window['go']['app']['App']['ListGameLogs']();
+}
+
+export function ListInstalledVersions(arg1) {
+  return window['go']['app']['App']['ListInstalledVersions'](arg1);
+}
+
+export function ListRunning() {
+  return window['go']['app']['App']['ListRunning']();
+}
+
+export function MigrateUserData(arg1, arg2) {
+  return window['go']['app']['App']['MigrateUserData'](arg1, arg2);
+}
+
+export function MoveInstallation(arg1) {
+  return window['go']['app']['App']['MoveInstallation'](arg1);
+}
+
 export function OpenFolder() {
   return window['go']['app']['App']['OpenFolder']();
 }
 
+export function PreviewLaunch(arg1) {
+  return window['go']['app']['App']['PreviewLaunch'](arg1);
+}
+
 export function RunDiagnostics() {
   return window['go']['app']['App']['RunDiagnostics']();
 }
 
+export function SaveButlerSettings(arg1) {
+  return window['go']['app']['App']['SaveButlerSettings'](arg1);
+}
+
 export function SaveDiagnosticReport() {
   return window['go']['app']['App']['SaveDiagnosticReport']();
 }
 
+export function SaveGraphicsSettings(arg1) {
+  return window['go']['app']['App']['SaveGraphicsSettings'](arg1);
+}
+
+export function SaveProfile(arg1, arg2) {
+  return window['go']['app']['App']['SaveProfile'](arg1, arg2);
+}
+
+export function SaveServerSettings(arg1) {
+  return window['go']['app']['App']['SaveServerSettings'](arg1);
+}
+
 export function SaveSettings(arg1) {
   return window['go']['app']['App']['SaveSettings'](arg1);
 }
 
+export function SelectGameDir() {
+  return window['go']['app']['App']['SelectGameDir']();
+}
+
+export function SendServerCommand(arg1, arg2) {
+  return window['go']['app']['App']['SendServerCommand'](arg1, arg2);
+}
+
+export function SetActiveVersion(arg1, arg2) {
+  return window['go']['app']['App']['SetActiveVersion'](arg1, arg2);
+}
+
 export function SetCurrentProfile(arg1) {
   return window['go']['app']['App']['SetCurrentProfile'](arg1);
 }
@@ -82,14 +174,30 @@ export function SetNick(arg1) {
   return window['go']['app']['App']['SetNick'](arg1);
 }
 
+export function StartServer() {
+  return window['go']['app']['App']['StartServer']();
+}
+
+export function Stop(arg1) {
+  return window['go']['app']['App']['Stop'](arg1);
+}
+
 export function StopGame() {
   return window['go']['app']['App']['StopGame']();
 }
 
+export function StopServer(arg1) {
+  return window['go']['app']['App']['StopServer'](arg1);
+}
+
 export function Update() {
   return window['go']['app']['App']['Update']();
 }
 
-export function UpdateProfile(arg1, arg2) {
-  return window['go']['app']['App']['UpdateProfile'](arg1, arg2);
+export function UpdateProfile(arg1, arg2, arg3) {
+  return window['go']['app']['App']['UpdateProfile'](arg1, arg2, arg3);
+}
+
+export function VerifyVersion(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['VerifyVersion'](arg1, arg2, arg3, arg4);
 }
@@ -76,6 +76,28 @@ export namespace app {
 	    }
 	}
 	
+	export class GraphicsInfo {
+	    session_type?: string;
+	    wayland_display?: string;
+	    display?: string;
+	    display_backend: string;
+	    gpu_offload: string;
+	    env?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GraphicsInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session_type = source["session_type"];
+	        this.wayland_display = source["wayland_display"];
+	        this.display = source["display"];
+	        this.display_backend = source["display_backend"];
+	        this.gpu_offload = source["gpu_offload"];
+	        this.env = source["env"];
+	    }
+	}
 	export class DiskSpaceInfo {
 	    install_directory: string;
 	    error?: string;
@@ -114,6 +136,7 @@ export namespace app {
 	    install_path: string;
 	    jre_installed: boolean;
 	    butler_installed: boolean;
+	    butler_version?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new InstallationInfo(source);
@@ -126,6 +149,7 @@ export namespace app {
 	        this.install_path = source["install_path"];
 	        this.jre_installed = source["jre_installed"];
 	        this.butler_installed = source["butler_installed"];
+	        this.butler_version = source["butler_version"];
 	    }
 	}
 	export class PlatformInfo {
@@ -157,6 +181,7 @@ export namespace app {
 	    local_installation: InstallationInfo;
 	    server_versions: ServerVersionInfo;
 	    disk_space: DiskSpaceInfo;
+	    graphics: GraphicsInfo;
 	
 	    static createFrom(source: any = {}) {
 	        return new DiagnosticReport(source);
@@ -171,6 +196,7 @@ export namespace app {
 	        this.local_installation = this.convertValues(source["local_installation"], InstallationInfo);
 	        this.server_versions = this.convertValues(source["server_versions"], ServerVersionInfo);
 	        this.disk_space = this.convertValues(source["disk_space"], DiskSpaceInfo);
+	        this.graphics = this.convertValues(source["graphics"], GraphicsInfo);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -210,22 +236,46 @@ export namespace app {
 	}
 	
 	
+	
 
 }
 
 export namespace config {
 	
+	export class ButlerSettings {
+	    version: string;
+	    sha256: string;
+	    path: string;
+	    native: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ButlerSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.sha256 = source["sha256"];
+	        this.path = source["path"];
+	        this.native = source["native"];
+	    }
+	}
 	export class GameSettings {
 	    minMemory: number;
 	    maxMemory: number;
 	    width: number;
 	    height: number;
-	    fullscreen: boolean;
+	    fullscreen?: boolean;
 	    javaArgs: string;
 	    gameDir: string;
 	    channel: string;
 	    gameVersion: number;
 	    onlineFix: boolean;
+	    stopTimeout: number;
+	    launcherBehavior: string;
+	    discordPresence: boolean;
+	    discordClientId: string;
+	    patchCacheLimit: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new GameSettings(source);
@@ -243,11 +293,48 @@ export namespace config {
 	        this.channel = source["channel"];
 	        this.gameVersion = source["gameVersion"];
 	        this.onlineFix = source["onlineFix"];
+	        this.stopTimeout = source["stopTimeout"];
+	        this.launcherBehavior = source["launcherBehavior"];
+	        this.discordPresence = source["discordPresence"];
+	        this.discordClientId = source["discordClientId"];
+	        this.patchCacheLimit = source["patchCacheLimit"];
+	    }
+	}
+	export class GraphicsSettings {
+	    displayBackend: string;
+	    gpuOffload: string;
+	    driPrime: string;
+	    sdlHints: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new GraphicsSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.displayBackend = source["displayBackend"];
+	        this.gpuOffload = source["gpuOffload"];
+	        this.driPrime = source["driPrime"];
+	        this.sdlHints = source["sdlHints"];
 	    }
 	}
 	export class Profile {
 	    id: string;
 	    name: string;
+	    wrapperCommand: string;
+	    preLaunchHook: string;
+	    postExitHook: string;
+	    env: Record<string, string>;
+	    extraArgs: string;
+	    isolatedUserData: boolean;
+	    uuidMode: string;
+	    customUuid: string;
+	    sandbox: boolean;
+	    sandboxNetwork: boolean;
+	    restartPolicy: string;
+	    maxRestarts: number;
+	    restartWindow: number;
+	    lastUuid: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Profile(source);
@@ -257,6 +344,40 @@ export namespace config {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
 	        this.name = source["name"];
+	        this.wrapperCommand = source["wrapperCommand"];
+	        this.preLaunchHook = source["preLaunchHook"];
+	        this.postExitHook = source["postExitHook"];
+	        this.env = source["env"];
+	        this.extraArgs = source["extraArgs"];
+	        this.isolatedUserData = source["isolatedUserData"];
+	        this.uuidMode = source["uuidMode"];
+	        this.customUuid = source["customUuid"];
+	        this.sandbox = source["sandbox"];
+	        this.sandboxNetwork = source["sandboxNetwork"];
+	        this.restartPolicy = source["restartPolicy"];
+	        this.maxRestarts = source["maxRestarts"];
+	        this.restartWindow = source["restartWindow"];
+	        this.lastUuid = source["lastUuid"];
+	    }
+	}
+	export class ServerSettings {
+	    minMemory: number;
+	    maxMemory: number;
+	    port: number;
+	    javaArgs: string;
+	    extraArgs: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServerSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.minMemory = source["minMemory"];
+	        this.maxMemory = source["maxMemory"];
+	        this.port = source["port"];
+	        this.javaArgs = source["javaArgs"];
+	        this.extraArgs = source["extraArgs"];
 	    }
 	}
 
@@ -264,6 +385,92 @@ export namespace config {
 
 export namespace diagnostics {
 	
+	export class CrashArtifact {
+	    name: string;
+	    source: string;
+	    path?: string;
+	    size: number;
+	    // Go type: time
+	    mod_time: any;
+	    skipped?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CrashArtifact(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.source = source["source"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.mod_time = this.convertValues(source["mod_time"], null);
+	        this.skipped = source["skipped"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GameCrash {
+	    session_id: string;
+	    channel: string;
+	    version: string;
+	    exit_code: number;
+	    signal?: string;
+	    // Go type: time
+	    started_at: any;
+	    duration_seconds: number;
+	    last_lines?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GameCrash(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session_id = source["session_id"];
+	        this.channel = source["channel"];
+	        this.version = source["version"];
+	        this.exit_code = source["exit_code"];
+	        this.signal = source["signal"];
+	        this.started_at = this.convertValues(source["started_at"], null);
+	        this.duration_seconds = source["duration_seconds"];
+	        this.last_lines = source["last_lines"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class SystemInfo {
 	    num_cpu: number;
 	    goos: string;
@@ -285,6 +492,7 @@ export namespace diagnostics {
 	    }
 	}
 	export class CrashReport {
+	    id: string;
 	    // Go type: time
 	    timestamp: any;
 	    app_version: string;
@@ -293,6 +501,8 @@ export namespace diagnostics {
 	    error?: hyerrors.AppError;
 	    system_info: SystemInfo;
 	    recent_logs?: string[];
+	    game?: GameCrash;
+	    artifacts?: CrashArtifact[];
 	
 	    static createFrom(source: any = {}) {
 	        return new CrashReport(source);
@@ -300,6 +510,7 @@ export namespace diagnostics {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
 	        this.timestamp = this.convertValues(source["timestamp"], null);
 	        this.app_version = source["app_version"];
 	        this.os = source["os"];
@@ -307,6 +518,170 @@ export namespace diagnostics {
 	        this.error = this.convertValues(source["error"], hyerrors.AppError);
 	        this.system_info = this.convertValues(source["system_info"], SystemInfo);
 	        this.recent_logs = source["recent_logs"];
+	        this.game = this.convertValues(source["game"], GameCrash);
+	        this.artifacts = this.convertValues(source["artifacts"], CrashArtifact);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+
+}
+
+export namespace game {
+	
+	export class EnvChange {
+	    name: string;
+	    value: string;
+	    previous?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EnvChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.value = source["value"];
+	        this.previous = source["previous"];
+	    }
+	}
+	export class GameLogInfo {
+	    sessionId: string;
+	    size: number;
+	    // Go type: time
+	    modTime: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new GameLogInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.size = source["size"];
+	        this.modTime = this.convertValues(source["modTime"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class InstalledVersion {
+	    channel: string;
+	    version: number;
+	    dir: string;
+	    path: string;
+	    size: number;
+	    // Go type: time
+	    installedAt: any;
+	    active: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new InstalledVersion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.channel = source["channel"];
+	        this.version = source["version"];
+	        this.dir = source["dir"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.installedAt = this.convertValues(source["installedAt"], null);
+	        this.active = source["active"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LaunchSettings {
+	    jvmOptions: string[];
+	    env: string[];
+	    applied: string[];
+	    gameDir: string;
+	    userDataDir: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LaunchSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jvmOptions = source["jvmOptions"];
+	        this.env = source["env"];
+	        this.applied = source["applied"];
+	        this.gameDir = source["gameDir"];
+	        this.userDataDir = source["userDataDir"];
+	    }
+	}
+	export class LaunchPreview {
+	    executable: string;
+	    args: string[];
+	    env: EnvChange[];
+	    workingDir: string;
+	    command: string;
+	    preLaunchHook?: string;
+	    settings?: LaunchSettings;
+	
+	    static createFrom(source: any = {}) {
+	        return new LaunchPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.executable = source["executable"];
+	        this.args = source["args"];
+	        this.env = this.convertValues(source["env"], EnvChange);
+	        this.workingDir = source["workingDir"];
+	        this.command = source["command"];
+	        this.preLaunchHook = source["preLaunchHook"];
+	        this.settings = this.convertValues(source["settings"], LaunchSettings);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -327,6 +702,53 @@ export namespace diagnostics {
 		    return a;
 		}
 	}
+	
+	export class UUIDChange {
+	    previous: string;
+	    next: string;
+	    changed: boolean;
+	    worlds: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new UUIDChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.previous = source["previous"];
+	        this.next = source["next"];
+	        this.changed = source["changed"];
+	        this.worlds = source["worlds"];
+	    }
+	}
+	export class VerifyResult {
+	    channel: string;
+	    dir: string;
+	    version: number;
+	    checked: number;
+	    missing: string[];
+	    changed: string[];
+	    extra: string[];
+	    removed: string[];
+	    repaired: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new VerifyResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.channel = source["channel"];
+	        this.dir = source["dir"];
+	        this.version = source["version"];
+	        this.checked = source["checked"];
+	        this.missing = source["missing"];
+	        this.changed = source["changed"];
+	        this.extra = source["extra"];
+	        this.removed = source["removed"];
+	        this.repaired = source["repaired"];
+	    }
+	}
 
 }
 
@@ -374,6 +796,151 @@ export namespace hyerrors {
 
 }
 
+export namespace instance {
+	
+	export class Info {
+	    id: string;
+	    kind: string;
+	    profileId: string;
+	    profileName: string;
+	    channel: string;
+	    version: string;
+	    sessionId?: string;
+	    pid: number;
+	    // Go type: time
+	    startedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Info(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.profileId = source["profileId"];
+	        this.profileName = source["profileName"];
+	        this.channel = source["channel"];
+	        this.version = source["version"];
+	        this.sessionId = source["sessionId"];
+	        this.pid = source["pid"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace patch {
+	
+	export class CacheEntry {
+	    key: string;
+	    channel: string;
+	    os: string;
+	    arch: string;
+	    from: number;
+	    to: number;
+	    path: string;
+	    size: number;
+	    sha256: string;
+	    // Go type: time
+	    added: any;
+	    // Go type: time
+	    lastUsed: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.channel = source["channel"];
+	        this.os = source["os"];
+	        this.arch = source["arch"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.sha256 = source["sha256"];
+	        this.added = this.convertValues(source["added"], null);
+	        this.lastUsed = this.convertValues(source["lastUsed"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CacheInfo {
+	    dir: string;
+	    entries: CacheEntry[];
+	    totalSize: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dir = source["dir"];
+	        this.entries = this.convertValues(source["entries"], CacheEntry);
+	        this.totalSize = source["totalSize"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace updater {
 	
 	export class Asset {
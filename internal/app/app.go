@@ -2,17 +2,23 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
+	"sync"
 
 	"HyLauncher/internal/config"
+	"HyLauncher/internal/diagnostics"
+	"HyLauncher/internal/discord"
 	"HyLauncher/internal/env"
 	"HyLauncher/internal/game"
+	"HyLauncher/internal/instance"
 	"HyLauncher/internal/patch"
 	"HyLauncher/internal/progress"
+	"HyLauncher/internal/server"
 	"HyLauncher/pkg/hyerrors"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -21,10 +27,23 @@ import (
 var AppVersion string = config.Default().Version
 
 type App struct {
-	ctx      context.Context
-	cfg      *config.Config
-	gameCmd  *exec.Cmd
-	progress *progress.Reporter
+	ctx       context.Context
+	cfgMu     sync.Mutex // guards cfg, the game goroutines write to it too
+	cfg       *config.Config
+	instances *instance.Manager
+	progress  *progress.Reporter
+	reporter  *diagnostics.Reporter
+
+	serversMu sync.Mutex
+	servers   map[string]*server.Server
+
+	presenceMu      sync.Mutex
+	presence        *discord.Client
+	presenceUpdates chan *discord.Activity // applied in order by presenceWorker
+	presenceStop    chan struct{}
+
+	restartsMu      sync.Mutex
+	pendingRestarts map[string]chan struct{} // closed to cancel a restart waiting out its delay
 }
 
 type GameVersions struct {
@@ -35,8 +54,19 @@ type GameVersions struct {
 
 func NewApp() *App {
 	cfg, _ := config.Load()
+	if cfg != nil {
+		env.SetInstallDir(cfg.Settings.GameDir)
+		patch.SetCacheLimit(int64(cfg.Settings.PatchCacheLimit) << 20)
+		patch.SetButlerSettings(cfg.Butler)
+	}
 	return &App{
-		cfg: cfg,
+		cfg:       cfg,
+		instances: instance.NewManager(),
+		servers:   make(map[string]*server.Server),
+
+		presenceUpdates: make(chan *discord.Activity, presenceQueueSize),
+		presenceStop:    make(chan struct{}),
+		pendingRestarts: make(map[string]chan struct{}),
 	}
 }
 
@@ -47,6 +77,12 @@ func (a *App) Startup(ctx context.Context) {
 	fmt.Println("Application starting up...")
 	fmt.Printf("Current launcher version: %s\n", AppVersion)
 
+	reporter, err := diagnostics.NewReporter(AppVersion)
+	if err != nil {
+		fmt.Printf("Warning: crash reporting disabled: %v\n", err)
+	}
+	a.reporter = reporter
+
 	go func() {
 		fmt.Println("Creating folders...")
 		env.CreateFolders()
@@ -62,6 +98,14 @@ func (a *App) Startup(ctx context.Context) {
 		fmt.Println("Starting cleanup")
 		env.CleanupLauncher()
 	}()
+
+	go a.presenceWorker()
+}
+
+// Shutdown runs when the launcher window closes
+func (a *App) Shutdown(ctx context.Context) {
+	close(a.presenceStop)
+	a.closePresence()
 }
 
 // handleError creates an AppError, emits it to frontend, and returns it
@@ -86,7 +130,7 @@ func (a *App) emitError(err error) {
 
 func (a *App) GetVersions(channel string) GameVersions {
 	if channel == "" {
-		channel = a.cfg.Settings.Channel
+		channel = a.currentChannel()
 	}
 
 	current := patch.GetLocalVersion(channel)
@@ -124,14 +168,12 @@ func (a *App) DownloadAndLaunch(playerName string) error {
 		)
 	}
 
-	channel := a.cfg.Settings.Channel
-	if channel == "" {
-		channel = "release"
-	}
-	targetVersion := a.cfg.Settings.GameVersion
+	cfg := a.configSnapshot()
+	channel := a.currentChannel()
+	targetVersion := cfg.Settings.GameVersion
 
 	// Ensure game is installed
-	if err := game.EnsureInstalledWithOptions(a.ctx, channel, targetVersion, a.cfg.Settings.OnlineFix, a.progress); err != nil {
+	if err := game.EnsureInstalledWithOptions(a.ctx, channel, targetVersion, cfg.Settings.OnlineFix, a.progress); err != nil {
 		wrappedErr := hyerrors.NewAppError(hyerrors.ErrorTypeGame, "Failed to install or update game", err)
 		a.emitError(wrappedErr)
 		return wrappedErr
@@ -140,47 +182,152 @@ func (a *App) DownloadAndLaunch(playerName string) error {
 	// Launch the game
 	a.progress.Report(progress.StageLaunch, 100, "Launching game...")
 
-	// Use the current profile's ID as the UUID to ensure persistence across name changes
-	// and consistency with the config file
-	playerUUID := a.cfg.CurrentProfile
+	profile := a.GetCurrentProfile()
+	launchOpts, err := game.NewLaunchOptions(&cfg, profile, playerName)
+	if err != nil {
+		return a.handleError(hyerrors.ErrorTypeValidation, err.Error(), err)
+	}
+
+	return a.launchGame(launchOpts, game.NewRestartTracker(profile))
+}
 
-	versionStr := "latest"
-	if a.cfg.Settings.GameVersion != 0 {
-		versionStr = strconv.Itoa(a.cfg.Settings.GameVersion)
+// launchGame starts the client and watches it until it exits, restarting it
+// when the profile's restart policy asks for it
+func (a *App) launchGame(launchOpts game.LaunchOptions, restarts *game.RestartTracker) error {
+	profile := launchOpts.Profile
+
+	sessionLog, err := game.NewSessionLog(func(line game.LogLine) {
+		runtime.EventsEmit(a.ctx, "game-log", line)
+	})
+	if err != nil {
+		fmt.Printf("Warning: game output will not be logged: %v\n", err)
 	}
 
-	cmd, err := game.Launch(playerName, channel, playerUUID, versionStr, a.cfg.Settings.OnlineFix)
+	launchOpts.Log = sessionLog
+	behavior := a.launcherBehavior(profile)
+	launchOpts.Detached = behavior == BehaviorClose
+
+	cmd, launchSettings, err := game.Launch(launchOpts)
 	if err != nil {
+		if sessionLog != nil {
+			_ = sessionLog.Close()
+		}
+		var hookErr *game.HookError
+		if errors.As(err, &hookErr) {
+			return a.handleError(hyerrors.ErrorTypeConfig, "Pre-launch hook failed, game was not started", err)
+		}
+		if errors.Is(err, game.ErrSandboxUnavailable) {
+			return a.handleError(hyerrors.ErrorTypeConfig, err.Error(), err)
+		}
 		wrappedErr := hyerrors.NewAppError(hyerrors.ErrorTypeGame, "Failed to launch game", err)
 		a.emitError(wrappedErr)
 		return wrappedErr
 	}
 
-	a.gameCmd = cmd
-	runtime.EventsEmit(a.ctx, "game-launched", nil)
+	info := instance.Info{
+		Kind:        instance.KindGame,
+		ProfileID:   profile.ID,
+		ProfileName: profile.Name,
+		Channel:     launchOpts.Channel,
+		Version:     launchOpts.Version,
+	}
+	if sessionLog != nil {
+		info.SessionID = sessionLog.ID
+	}
+	inst := a.instances.Track(cmd, info)
+	a.rememberUUID(profile.ID, launchOpts.PlayerUUID)
+	runtime.EventsEmit(a.ctx, "game-launched", GameEvent{Instance: inst.Info(), Settings: launchSettings})
+	mon := a.startMonitor(inst)
+	a.presenceLaunched(inst.Info())
+	a.applyLauncherBehavior(behavior)
 
 	// Monitor game process
 	go func() {
-		if err := cmd.Wait(); err != nil {
+		if err := inst.Wait(); err != nil {
 			fmt.Printf("Game process exited with error: %v\n", err)
 		}
-		a.gameCmd = nil
-		runtime.EventsEmit(a.ctx, "game-closed", nil)
+
+		exitInfo := game.GetExitInfo(inst.ProcessState())
+		if sessionLog != nil {
+			// Output is complete once the process is gone, the crash report needs its last line
+			sessionLog.Flush()
+		}
+		if exitInfo.Crashed && !inst.Stopping() {
+			a.reportGameCrash(inst.Info(), exitInfo, sessionLog, launchSettings)
+			a.presenceCrashed(inst.Info())
+		} else {
+			a.presenceClosed()
+		}
+
+		closed := GameEvent{Instance: inst.Info(), Exit: &exitInfo}
+		if mon != nil {
+			stats := mon.Stats()
+			closed.Stats = &stats
+			fmt.Printf("Game session used peak %d MB RSS, average %.1f%% CPU\n", stats.PeakRSS/1024/1024, stats.AvgCPU)
+		}
+		runtime.EventsEmit(a.ctx, "game-closed", closed)
+
+		decision := restarts.Next(exitInfo, inst.Stopping())
+		if !decision.Restart {
+			a.restoreLauncher(behavior)
+		}
+
+		if profile.PostExitHook != "" {
+			hookCtx := game.NewHookContext(launchOpts, launchSettings)
+			hookCtx.ExitCode = &exitInfo.ExitCode
+
+			var output io.Writer
+			if sessionLog != nil {
+				output = sessionLog.Writer("post-exit")
+			}
+			if err := game.RunHook(context.Background(), "post-exit", profile.PostExitHook, hookCtx, output); err != nil {
+				fmt.Printf("Warning: %v\n", err)
+			}
+		}
+
+		if sessionLog != nil {
+			_ = sessionLog.Close()
+		}
+
+		a.handleRestart(launchOpts, restarts, inst.Info(), decision)
 	}()
 
 	return nil
 }
 
+// currentChannel returns the selected patch channel
+func (a *App) currentChannel() string {
+	if channel := a.GetSettings().Channel; channel != "" {
+		return channel
+	}
+	return "release"
+}
+
+// currentVersionDir returns the install directory name of the selected game version
+func (a *App) currentVersionDir() string {
+	return game.VersionDir(a.GetSettings().GameVersion)
+}
+
+// StopGame stops every running game client and cancels pending restarts
 func (a *App) StopGame() {
-	if a.gameCmd != nil && a.gameCmd.Process != nil {
-		if err := a.gameCmd.Process.Kill(); err != nil {
-			fmt.Printf("Failed to kill game process: %v\n", err)
+	a.cancelRestarts()
+	for _, info := range a.instances.ListKind(instance.KindGame) {
+		if err := a.instances.Stop(info.ID, a.stopTimeout()); err != nil {
+			fmt.Printf("Failed to stop game process: %v\n", err)
 		}
-		a.gameCmd = nil
-		runtime.EventsEmit(a.ctx, "game-closed", nil)
 	}
 }
 
+// ListGameLogs returns the stored game session logs, newest first
+func (a *App) ListGameLogs() ([]game.GameLogInfo, error) {
+	return game.ListGameLogs()
+}
+
+// GetGameLog returns the captured output of a game session
+func (a *App) GetGameLog(sessionID string) (string, error) {
+	return game.ReadGameLog(sessionID)
+}
+
 func (a *App) GetLogs() (string, error) {
 	logFile := filepath.Join(env.GetDefaultAppDir(), "logs", "errors.log")
 	data, err := os.ReadFile(logFile)
@@ -189,3 +336,28 @@ func (a *App) GetLogs() (string, error) {
 	}
 	return string(data), nil
 }
+
+// PreviewLaunch resolves the launch of a profile, the current one when empty,
+// and returns the command that would run without starting it
+func (a *App) PreviewLaunch(profileID string) (*game.LaunchPreview, error) {
+	profile := a.GetCurrentProfile()
+	if profileID != "" {
+		p, ok := a.findProfile(profileID)
+		if !ok {
+			return nil, fmt.Errorf("profile not found")
+		}
+		profile = p
+	}
+
+	cfg := a.configSnapshot()
+	launchOpts, err := game.NewLaunchOptions(&cfg, profile, profile.Name)
+	if err != nil {
+		return nil, hyerrors.NewAppError(hyerrors.ErrorTypeValidation, err.Error(), err)
+	}
+
+	preview, err := game.PreviewLaunch(launchOpts)
+	if err != nil {
+		return nil, hyerrors.NewAppError(hyerrors.ErrorTypeGame, "Failed to prepare launch", err)
+	}
+	return preview, nil
+}
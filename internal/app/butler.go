@@ -0,0 +1,65 @@
+package app
+
+import (
+	"encoding/hex"
+	"path/filepath"
+
+	"HyLauncher/internal/config"
+	"HyLauncher/internal/game"
+	"HyLauncher/internal/patch"
+	"HyLauncher/pkg/hyerrors"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+func (a *App) GetButlerSettings() config.ButlerSettings {
+	return a.configSnapshot().Butler
+}
+
+// SaveButlerSettings pins a butler version and checksum, the next install replaces a mismatching binary
+func (a *App) SaveButlerSettings(settings config.ButlerSettings) error {
+	if settings.SHA256 != "" {
+		if b, err := hex.DecodeString(settings.SHA256); err != nil || len(b) != 32 {
+			return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, "SHA256 must be 64 hex characters", err)
+		}
+	}
+	if settings.Path == "" && settings.Version == "" {
+		return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, "Pin a butler version or choose a local butler file", nil)
+	}
+
+	return a.updateConfig(func(cfg *config.Config) error {
+		cfg.Butler = settings
+		patch.SetButlerSettings(settings)
+		return nil
+	})
+}
+
+// ImportButler installs a local butler binary or zip for machines without access to broth
+func (a *App) ImportButler() (string, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Select butler binary or zip",
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	// The chosen file is trusted as is, a pinned checksum belongs to the download
+	previous := a.GetButlerSettings()
+	settings := previous
+	settings.Path = filepath.Clean(path)
+	settings.SHA256 = ""
+	if err := a.SaveButlerSettings(settings); err != nil {
+		return "", err
+	}
+
+	if _, err := game.ReinstallButler(a.ctx, a.progress); err != nil {
+		_ = a.SaveButlerSettings(previous)
+		return "", a.handleError(hyerrors.ErrorTypeFileSystem, "Failed to import butler", err)
+	}
+
+	version, err := patch.ButlerVersion()
+	if err != nil {
+		return "", a.handleError(hyerrors.ErrorTypeFileSystem, "Imported butler does not run", err)
+	}
+	return version, nil
+}
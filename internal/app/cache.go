@@ -0,0 +1,20 @@
+package app
+
+import (
+	"HyLauncher/internal/game"
+	"HyLauncher/internal/patch"
+	"HyLauncher/pkg/hyerrors"
+)
+
+// GetPatchCache lists the cached game patches and the cache size
+func (a *App) GetPatchCache() patch.CacheInfo {
+	return patch.GetCacheInfo()
+}
+
+// ClearPatchCache removes every cached game patch
+func (a *App) ClearPatchCache() error {
+	if err := game.ClearPatchCache(); err != nil {
+		return a.handleError(hyerrors.ErrorTypeFileSystem, "Failed to clear the patch cache", err)
+	}
+	return nil
+}
@@ -2,30 +2,70 @@ package app
 
 import (
 	"HyLauncher/internal/config"
+	"HyLauncher/internal/game"
+	"HyLauncher/internal/instance"
+	"HyLauncher/internal/patch"
+	"HyLauncher/pkg/hyerrors"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
 )
 
+// configSnapshot returns a copy of the configuration that is safe to read
+// without the lock, e.g. from the goroutines watching a game
+func (a *App) configSnapshot() config.Config {
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+
+	cfg := *a.cfg
+	cfg.Profiles = append([]config.Profile(nil), a.cfg.Profiles...)
+	return cfg
+}
+
+// updateConfig changes the configuration under the lock and saves it when fn succeeds
+func (a *App) updateConfig(fn func(cfg *config.Config) error) error {
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+
+	if err := fn(a.cfg); err != nil {
+		return err
+	}
+	return config.Save(a.cfg)
+}
+
 func (a *App) GetProfiles() []config.Profile {
-	return a.cfg.Profiles
+	return a.configSnapshot().Profiles
 }
 
 func (a *App) GetCurrentProfile() config.Profile {
-	for _, p := range a.cfg.Profiles {
-		if p.ID == a.cfg.CurrentProfile {
+	cfg := a.configSnapshot()
+	for _, p := range cfg.Profiles {
+		if p.ID == cfg.CurrentProfile {
 			return p
 		}
 	}
-	if len(a.cfg.Profiles) > 0 {
-		return a.cfg.Profiles[0]
+	if len(cfg.Profiles) > 0 {
+		return cfg.Profiles[0]
 	}
 	return config.Profile{}
 }
 
+// findProfile returns a profile by ID
+func (a *App) findProfile(id string) (config.Profile, bool) {
+	for _, p := range a.configSnapshot().Profiles {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return config.Profile{}, false
+}
+
 func (a *App) SetCurrentProfile(id string) error {
-	a.cfg.CurrentProfile = id
-	return config.Save(a.cfg)
+	return a.updateConfig(func(cfg *config.Config) error {
+		cfg.CurrentProfile = id
+		return nil
+	})
 }
 
 func (a *App) AddProfile(name string) (config.Profile, error) {
@@ -33,57 +73,202 @@ func (a *App) AddProfile(name string) (config.Profile, error) {
 		ID:   uuid.New().String(),
 		Name: name,
 	}
-	a.cfg.Profiles = append(a.cfg.Profiles, newProfile)
-	a.cfg.CurrentProfile = newProfile.ID
-	err := config.Save(a.cfg)
+	err := a.updateConfig(func(cfg *config.Config) error {
+		cfg.Profiles = append(cfg.Profiles, newProfile)
+		cfg.CurrentProfile = newProfile.ID
+		return nil
+	})
 	return newProfile, err
 }
 
-func (a *App) UpdateProfile(id string, name string) error {
-	for i, p := range a.cfg.Profiles {
-		if p.ID == id {
-			a.cfg.Profiles[i].Name = name
-			return config.Save(a.cfg)
+// UpdateProfile renames a profile. A rename that changes the player's UUID, as in
+// the offline UUID mode, is refused unless confirmUUIDChange is set.
+func (a *App) UpdateProfile(id string, name string, confirmUUIDChange bool) error {
+	return a.updateConfig(func(cfg *config.Config) error {
+		for i, p := range cfg.Profiles {
+			if p.ID == id {
+				updated := p
+				updated.Name = name
+				if err := guardUUIDChange(p, updated, confirmUUIDChange); err != nil {
+					return err
+				}
+				cfg.Profiles[i].Name = name
+				return nil
+			}
+		}
+		return fmt.Errorf("profile not found")
+	})
+}
+
+// SaveProfile updates every editable field of an existing profile. A change of the
+// player's UUID is refused unless confirmUUIDChange is set.
+func (a *App) SaveProfile(profile config.Profile, confirmUUIDChange bool) error {
+	if err := game.ValidateProfile(profile); err != nil {
+		return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, err.Error(), err)
+	}
+
+	return a.updateConfig(func(cfg *config.Config) error {
+		for i, p := range cfg.Profiles {
+			if p.ID == profile.ID {
+				if err := guardUUIDChange(p, profile, confirmUUIDChange); err != nil {
+					return err
+				}
+				profile.LastUUID = p.LastUUID
+				cfg.Profiles[i] = profile
+				return nil
+			}
+		}
+		return fmt.Errorf("profile not found")
+	})
+}
+
+// guardUUIDChange refuses an unconfirmed update that would give the player a new
+// identity, the UI asks with CheckProfileUUID first and shows the affected worlds
+func guardUUIDChange(current, updated config.Profile, confirmed bool) error {
+	if confirmed {
+		return nil
+	}
+
+	change, err := game.CheckUUIDChange(current, updated)
+	if err != nil {
+		return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, err.Error(), err)
+	}
+	if !change.Changed {
+		return nil
+	}
+
+	msg := fmt.Sprintf("This change would switch the player UUID from %s to %s", change.Previous, change.Next)
+	if len(change.Worlds) > 0 {
+		msg += fmt.Sprintf(", %d worlds may no longer recognize the player", len(change.Worlds))
+	}
+	return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, msg+". Confirm the change to save it.", nil)
+}
+
+// CheckProfileUUID reports whether saving the profile would change the player's UUID,
+// so the UI can warn before existing worlds stop recognizing the player
+func (a *App) CheckProfileUUID(profile config.Profile) (game.UUIDChange, error) {
+	current, ok := a.findProfile(profile.ID)
+	if !ok {
+		return game.UUIDChange{}, fmt.Errorf("profile not found")
+	}
+	return game.CheckUUIDChange(current, profile)
+}
+
+// errUnchanged skips the save in updateConfig when there is nothing to write
+var errUnchanged = errors.New("config unchanged")
+
+// rememberUUID stores the UUID a profile launched with
+func (a *App) rememberUUID(profileID, playerUUID string) {
+	err := a.updateConfig(func(cfg *config.Config) error {
+		for i, p := range cfg.Profiles {
+			if p.ID == profileID && p.LastUUID != playerUUID {
+				cfg.Profiles[i].LastUUID = playerUUID
+				return nil
+			}
 		}
+		return errUnchanged
+	})
+	if err != nil && err != errUnchanged {
+		fmt.Printf("Warning: failed to save config: %v\n", err)
 	}
-	return fmt.Errorf("profile not found")
 }
 
-func (a *App) DeleteProfile(id string) error {
-	if len(a.cfg.Profiles) <= 1 {
+// DeleteProfile removes a profile, its isolated UserData is deleted only when removeData is set
+func (a *App) DeleteProfile(id string, removeData bool) error {
+	if len(a.GetProfiles()) <= 1 {
 		return fmt.Errorf("cannot delete last profile")
 	}
+	if _, ok := a.findProfile(id); !ok {
+		return fmt.Errorf("profile not found")
+	}
 
-	index := -1
-	for i, p := range a.cfg.Profiles {
-		if p.ID == id {
-			index = i
-			break
+	if removeData {
+		if a.isProfileRunning(id) {
+			return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, "Close the game before deleting this profile's data", nil)
+		}
+		if err := game.DeleteIsolatedUserData(id); err != nil {
+			return hyerrors.NewAppError(hyerrors.ErrorTypeFileSystem, "Failed to delete profile data", err)
 		}
 	}
 
-	if index == -1 {
+	return a.updateConfig(func(cfg *config.Config) error {
+		index := -1
+		for i, p := range cfg.Profiles {
+			if p.ID == id {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return fmt.Errorf("profile not found")
+		}
+		if len(cfg.Profiles) <= 1 {
+			return fmt.Errorf("cannot delete last profile")
+		}
+
+		cfg.Profiles = append(cfg.Profiles[:index], cfg.Profiles[index+1:]...)
+		if cfg.CurrentProfile == id {
+			cfg.CurrentProfile = cfg.Profiles[0].ID
+		}
+		return nil
+	})
+}
+
+// MigrateUserData copies the shared UserData into the profile's isolated directory
+// (or moves it when move is set) and switches the profile to isolated data
+func (a *App) MigrateUserData(profileID string, move bool) error {
+	if _, ok := a.findProfile(profileID); !ok {
 		return fmt.Errorf("profile not found")
 	}
 
-	a.cfg.Profiles = append(a.cfg.Profiles[:index], a.cfg.Profiles[index+1:]...)
+	if len(a.instances.ListKind(instance.KindGame)) > 0 {
+		return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, "Close the game before migrating user data", nil)
+	}
 
-	if a.cfg.CurrentProfile == id {
-		a.cfg.CurrentProfile = a.cfg.Profiles[0].ID
+	if err := game.MigrateUserData(profileID, move); err != nil {
+		return hyerrors.NewAppError(hyerrors.ErrorTypeFileSystem, "Failed to migrate user data", err)
 	}
 
-	return config.Save(a.cfg)
+	return a.updateConfig(func(cfg *config.Config) error {
+		for i, p := range cfg.Profiles {
+			if p.ID == profileID {
+				cfg.Profiles[i].IsolatedUserData = true
+				return nil
+			}
+		}
+		return fmt.Errorf("profile not found")
+	})
+}
+
+func (a *App) isProfileRunning(profileID string) bool {
+	for _, info := range a.instances.ListKind(instance.KindGame) {
+		if info.ProfileID == profileID {
+			return true
+		}
+	}
+	return false
 }
 
 func (a *App) SetNick(nick string) error {
 	// Update name of current profile for compatibility
-	for i, p := range a.cfg.Profiles {
-		if p.ID == a.cfg.CurrentProfile {
-			a.cfg.Profiles[i].Name = nick
-			return config.Save(a.cfg)
+	err := a.updateConfig(func(cfg *config.Config) error {
+		for i, p := range cfg.Profiles {
+			if p.ID == cfg.CurrentProfile {
+				updated := p
+				updated.Name = nick
+				if err := guardUUIDChange(p, updated, false); err != nil {
+					return err
+				}
+				cfg.Profiles[i].Name = nick
+				return nil
+			}
 		}
+		return errUnchanged
+	})
+	if err == errUnchanged {
+		return nil
 	}
-	return nil
+	return err
 }
 
 func (a *App) GetNick() string {
@@ -95,10 +280,55 @@ func (a *App) GetLauncherVersion() string {
 }
 
 func (a *App) GetSettings() config.GameSettings {
-	return a.cfg.Settings
+	return a.configSnapshot().Settings
 }
 
 func (a *App) SaveSettings(settings config.GameSettings) error {
-	a.cfg.Settings = settings
-	return config.Save(a.cfg)
+	if settings.PatchCacheLimit < 0 {
+		return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, "Patch cache limit cannot be negative", nil)
+	}
+
+	switch settings.LauncherBehavior {
+	case "", BehaviorKeep, BehaviorHide, BehaviorMinimize, BehaviorClose:
+	default:
+		return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, fmt.Sprintf("Unknown launcher behavior %q", settings.LauncherBehavior), nil)
+	}
+
+	// A new game directory means moving the installation, never just pointing elsewhere
+	if normalizeGameDir(settings.GameDir) != normalizeGameDir(a.GetSettings().GameDir) {
+		if err := a.moveInstallation(settings.GameDir); err != nil {
+			return err
+		}
+	}
+
+	var previous config.GameSettings
+	err := a.updateConfig(func(cfg *config.Config) error {
+		settings.GameDir = cfg.Settings.GameDir
+		previous = cfg.Settings
+		cfg.Settings = settings
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	patch.SetCacheLimit(int64(settings.PatchCacheLimit) << 20)
+
+	if previous.DiscordPresence != settings.DiscordPresence || previous.DiscordClientID != settings.DiscordClientID {
+		a.refreshPresence()
+	}
+	return nil
+}
+
+func (a *App) GetGraphicsSettings() config.GraphicsSettings {
+	return a.configSnapshot().Graphics
+}
+
+func (a *App) SaveGraphicsSettings(settings config.GraphicsSettings) error {
+	if err := game.ValidateGraphicsSettings(settings); err != nil {
+		return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, err.Error(), err)
+	}
+	return a.updateConfig(func(cfg *config.Config) error {
+		cfg.Graphics = settings
+		return nil
+	})
 }
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"HyLauncher/internal/config"
+	"HyLauncher/internal/instance"
+)
+
+// TestConfigConcurrentAccess runs what a restarting game does next to what
+// the UI bindings do, -race reports any access to cfg outside the lock
+func TestConfigConcurrentAccess(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	cfg := config.Default()
+	cfg.Profiles = []config.Profile{{ID: "a", Name: "Alpha"}, {ID: "b", Name: "Beta"}}
+	cfg.CurrentProfile = "a"
+	a := &App{cfg: &cfg, instances: instance.NewManager()}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(4)
+		go func(i int) {
+			defer wg.Done()
+			a.rememberUUID("a", fmt.Sprintf("uuid-%d", i))
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			profile, _ := a.findProfile("b")
+			profile.ExtraArgs = fmt.Sprintf("--n %d", i)
+			if err := a.SaveProfile(profile, true); err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = a.GetSettings()
+			_ = a.GetProfiles()
+			_ = a.currentChannel()
+		}()
+		go func(i int) {
+			defer wg.Done()
+			if _, err := a.AddProfile(fmt.Sprintf("extra-%d", i)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(a.GetProfiles()); got != 22 {
+		t.Errorf("%d profiles, want 22", got)
+	}
+	if profile, _ := a.findProfile("a"); profile.LastUUID == "" {
+		t.Error("the launch UUID was not remembered")
+	}
+}
@@ -3,11 +3,49 @@ package app
 import (
 	"HyLauncher/internal/diagnostics"
 	"HyLauncher/internal/env"
+	"HyLauncher/internal/game"
+	"HyLauncher/internal/instance"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// reportGameCrash saves a crash report for an abnormal game exit and notifies the frontend
+func (a *App) reportGameCrash(info instance.Info, exitInfo game.ExitInfo, sessionLog *game.SessionLog, launchSettings *game.LaunchSettings) {
+	crash := diagnostics.GameCrash{
+		Channel:         info.Channel,
+		Version:         info.Version,
+		ExitCode:        exitInfo.ExitCode,
+		Signal:          exitInfo.Signal,
+		StartedAt:       info.StartedAt,
+		DurationSeconds: time.Since(info.StartedAt).Seconds(),
+	}
+	if sessionLog != nil {
+		crash.SessionID = sessionLog.ID
+		crash.LastLines = sessionLog.Tail()
+	}
+
+	fmt.Printf("Game crashed (exit code %d, signal %q) after %.0fs\n", crash.ExitCode, crash.Signal, crash.DurationSeconds)
+
+	var report *diagnostics.CrashReport
+	if a.reporter != nil {
+		saved, err := a.reporter.SaveGameCrash(crash, game.CrashArtifactSources(launchSettings))
+		if err != nil {
+			fmt.Printf("Warning: failed to save crash report: %v\n", err)
+		}
+		report = saved
+	}
+	if report == nil {
+		report = &diagnostics.CrashReport{Timestamp: time.Now(), AppVersion: AppVersion, Game: &crash}
+	}
+
+	runtime.EventsEmit(a.ctx, "game-crashed", report)
+}
+
 // GetCrashReports returns all crash reports
 func (a *App) GetCrashReports() ([]diagnostics.CrashReport, error) {
 	crashDir := filepath.Join(env.GetDefaultAppDir(), "crashes")
@@ -3,7 +3,9 @@ package app
 // TODO FULL REFACTOR
 
 import (
+	"HyLauncher/internal/config"
 	"HyLauncher/internal/env"
+	"HyLauncher/internal/game"
 	"HyLauncher/internal/patch"
 	"fmt"
 	"os"
@@ -21,6 +23,7 @@ type DiagnosticReport struct {
 	LocalInstallation InstallationInfo  `json:"local_installation"`
 	ServerVersions    ServerVersionInfo `json:"server_versions"`
 	DiskSpace         DiskSpaceInfo     `json:"disk_space"`
+	Graphics          GraphicsInfo      `json:"graphics"`
 }
 
 type PlatformInfo struct {
@@ -45,6 +48,7 @@ type InstallationInfo struct {
 	InstallPath     string `json:"install_path"`
 	JREInstalled    bool   `json:"jre_installed"`
 	ButlerInstalled bool   `json:"butler_installed"`
+	ButlerVersion   string `json:"butler_version,omitempty"`
 }
 
 type ServerVersionInfo struct {
@@ -54,6 +58,15 @@ type ServerVersionInfo struct {
 	Error         string   `json:"error,omitempty"`
 }
 
+type GraphicsInfo struct {
+	SessionType    string   `json:"session_type,omitempty"`
+	WaylandDisplay string   `json:"wayland_display,omitempty"`
+	Display        string   `json:"display,omitempty"`
+	DisplayBackend string   `json:"display_backend"`
+	GPUOffload     string   `json:"gpu_offload"`
+	Env            []string `json:"env,omitempty"`
+}
+
 type DiskSpaceInfo struct {
 	InstallDirectory string `json:"install_directory"`
 	Error            string `json:"error,omitempty"`
@@ -86,6 +99,9 @@ func (a *App) RunDiagnostics() (*DiagnosticReport, error) {
 	// Disk space check
 	report.DiskSpace = checkDiskSpace()
 
+	// Graphics environment the client would get
+	report.Graphics = checkGraphics(a.GetGraphicsSettings())
+
 	return report, nil
 }
 
@@ -123,7 +139,7 @@ func checkConnectivity() ConnectivityInfo {
 
 func checkLocalInstallation() InstallationInfo {
 	info := InstallationInfo{
-		InstallPath:    env.GetDefaultAppDir(),
+		InstallPath:    env.GetInstallDir(),
 		CurrentVersion: patch.GetLocalVersion("release"),
 	}
 
@@ -132,12 +148,12 @@ func checkLocalInstallation() InstallationInfo {
 	if runtime.GOOS == "windows" {
 		gameClient += ".exe"
 	}
-	clientPath := filepath.Join(env.GetDefaultAppDir(), "release", "package", "game", "latest", "Client", gameClient)
+	clientPath := filepath.Join(env.GetGameDir("release", "latest"), "Client", gameClient)
 	_, err := os.Stat(clientPath)
 	info.GameInstalled = err == nil
 
 	// Check if JRE is installed
-	jreDir := filepath.Join(env.GetDefaultAppDir(), "release", "package", "jre", "latest")
+	jreDir := filepath.Join(env.GetJREDir(), "latest")
 	javaExec := filepath.Join(jreDir, "bin", "java")
 	if runtime.GOOS == "windows" {
 		javaExec += ".exe"
@@ -146,12 +162,15 @@ func checkLocalInstallation() InstallationInfo {
 	info.JREInstalled = err == nil
 
 	// Check if Butler is installed
-	butlerPath := filepath.Join(env.GetDefaultAppDir(), "tools", "butler", "butler")
-	if runtime.GOOS == "windows" {
-		butlerPath += ".exe"
-	}
-	_, err = os.Stat(butlerPath)
+	_, err = os.Stat(patch.ButlerPath())
 	info.ButlerInstalled = err == nil
+	if info.ButlerInstalled {
+		if version, err := patch.ButlerVersion(); err == nil {
+			info.ButlerVersion = version
+		} else {
+			info.ButlerVersion = err.Error()
+		}
+	}
 
 	return info
 }
@@ -180,14 +199,26 @@ func checkServerVersions() ServerVersionInfo {
 	return info
 }
 
+func checkGraphics(settings config.GraphicsSettings) GraphicsInfo {
+	vars, _ := game.GraphicsEnv(settings)
+	return GraphicsInfo{
+		SessionType:    os.Getenv("XDG_SESSION_TYPE"),
+		WaylandDisplay: os.Getenv("WAYLAND_DISPLAY"),
+		Display:        os.Getenv("DISPLAY"),
+		DisplayBackend: settings.DisplayBackend,
+		GPUOffload:     settings.GPUOffload,
+		Env:            vars,
+	}
+}
+
 func checkDiskSpace() DiskSpaceInfo {
 	info := DiskSpaceInfo{
-		InstallDirectory: env.GetDefaultAppDir(),
+		InstallDirectory: env.GetInstallDir(),
 	}
 
 	// Note: Getting accurate disk space in a cross-platform way is complex
 	// This is a simplified version
-	stat, err := os.Stat(env.GetDefaultAppDir())
+	stat, err := os.Stat(env.GetInstallDir())
 	if err != nil {
 		info.Error = fmt.Sprintf("Cannot access install directory: %v", err)
 		return info
@@ -223,6 +254,7 @@ Game Installed: %v
 Current Version: %s
 JRE Installed: %v
 Butler Installed: %v
+Butler Version: %s
 
 --- Server Versions ---
 Latest Version Found: %d
@@ -248,6 +280,7 @@ Install Directory: %s
 		report.LocalInstallation.CurrentVersion,
 		report.LocalInstallation.JREInstalled,
 		report.LocalInstallation.ButlerInstalled,
+		report.LocalInstallation.ButlerVersion,
 		report.ServerVersions.LatestVersion,
 		report.ServerVersions.FoundVersions,
 		formatServerError(report.ServerVersions),
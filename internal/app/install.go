@@ -0,0 +1,52 @@
+package app
+
+import (
+	"path/filepath"
+
+	"HyLauncher/internal/config"
+	"HyLauncher/internal/env"
+	"HyLauncher/internal/game"
+	"HyLauncher/pkg/hyerrors"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// SelectGameDir opens a folder picker for a new install location
+func (a *App) SelectGameDir() (string, error) {
+	return runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:                "Select game directory",
+		DefaultDirectory:     env.GetInstallDir(),
+		CanCreateDirectories: true,
+	})
+}
+
+// MoveInstallation relocates game files, the JRE and the cache to target and
+// makes it the install root. An empty target moves everything back to the default.
+func (a *App) MoveInstallation(target string) error {
+	return a.moveInstallation(target)
+}
+
+// moveInstallation moves the install root and saves the new GameDir
+func (a *App) moveInstallation(target string) error {
+	if len(a.instances.List()) > 0 {
+		return a.handleError(hyerrors.ErrorTypeValidation, "Stop the game and servers before moving the installation", nil)
+	}
+
+	gameDir := normalizeGameDir(target)
+	if err := game.MoveInstallation(a.ctx, gameDir, a.progress); err != nil {
+		return a.handleError(hyerrors.ErrorTypeFileSystem, "Failed to move the installation", err)
+	}
+
+	return a.updateConfig(func(cfg *config.Config) error {
+		cfg.Settings.GameDir = gameDir
+		return nil
+	})
+}
+
+// normalizeGameDir maps the default app directory to the empty GameDir
+func normalizeGameDir(dir string) string {
+	if dir == "" || filepath.Clean(dir) == env.GetDefaultAppDir() {
+		return ""
+	}
+	return filepath.Clean(dir)
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"HyLauncher/internal/config"
+	"HyLauncher/internal/game"
+	"HyLauncher/internal/instance"
+	"HyLauncher/internal/monitor"
+	"HyLauncher/pkg/hyerrors"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// GameEvent is the payload of the game-launched and game-closed events
+type GameEvent struct {
+	Instance instance.Info        `json:"instance"`
+	Settings *game.LaunchSettings `json:"settings,omitempty"`
+	Exit     *game.ExitInfo       `json:"exit,omitempty"`
+	Stats    *monitor.Stats       `json:"stats,omitempty"` // session peak and average, on close
+}
+
+// RestartEvent is the payload of the game-restarting and game-restart-abandoned events
+type RestartEvent struct {
+	Instance     instance.Info `json:"instance"`
+	Reason       string        `json:"reason"`
+	Attempt      int           `json:"attempt"`
+	Max          int           `json:"max"`
+	DelaySeconds float64       `json:"delaySeconds"`
+}
+
+// handleRestart relaunches a game that exited when the restart policy decided so
+func (a *App) handleRestart(launchOpts game.LaunchOptions, restarts *game.RestartTracker, info instance.Info, decision game.RestartDecision) {
+	event := RestartEvent{
+		Instance:     info,
+		Reason:       decision.Reason,
+		Attempt:      decision.Attempt,
+		Max:          decision.Max,
+		DelaySeconds: decision.Delay.Seconds(),
+	}
+
+	if decision.GaveUp {
+		fmt.Printf("Not restarting %s: %s\n", info.ProfileName, decision.Reason)
+		runtime.EventsEmit(a.ctx, "game-restart-abandoned", event)
+		return
+	}
+	if !decision.Restart {
+		return
+	}
+
+	cancel := make(chan struct{})
+	a.restartsMu.Lock()
+	a.pendingRestarts[info.ID] = cancel
+	a.restartsMu.Unlock()
+
+	fmt.Printf("Restarting %s in %s (%d/%d): %s\n", info.ProfileName, decision.Delay, decision.Attempt, decision.Max, decision.Reason)
+	runtime.EventsEmit(a.ctx, "game-restarting", event)
+
+	timer := time.NewTimer(decision.Delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-cancel:
+	}
+
+	a.restartsMu.Lock()
+	cancelled := a.pendingRestarts[info.ID] != cancel
+	delete(a.pendingRestarts, info.ID)
+	a.restartsMu.Unlock()
+
+	if cancelled {
+		fmt.Printf("Restart of %s cancelled\n", info.ProfileName)
+		event.Reason = "restart cancelled"
+		runtime.EventsEmit(a.ctx, "game-restart-abandoned", event)
+		a.restoreLauncher(a.launcherBehavior(launchOpts.Profile))
+		return
+	}
+
+	if err := a.launchGame(launchOpts, restarts); err != nil {
+		event.Reason = "restart failed: " + err.Error()
+		runtime.EventsEmit(a.ctx, "game-restart-abandoned", event)
+		a.restoreLauncher(a.launcherBehavior(launchOpts.Profile))
+	}
+}
+
+// cancelRestart stops a restart waiting out its delay, false when none is pending
+func (a *App) cancelRestart(id string) bool {
+	a.restartsMu.Lock()
+	defer a.restartsMu.Unlock()
+
+	cancel, ok := a.pendingRestarts[id]
+	if ok {
+		delete(a.pendingRestarts, id)
+		close(cancel)
+	}
+	return ok
+}
+
+// cancelRestarts stops every restart waiting out its delay
+func (a *App) cancelRestarts() {
+	a.restartsMu.Lock()
+	defer a.restartsMu.Unlock()
+
+	for id, cancel := range a.pendingRestarts {
+		delete(a.pendingRestarts, id)
+		close(cancel)
+	}
+}
+
+// How often a running game's resource usage is sampled
+const statsInterval = 3 * time.Second
+
+// startMonitor emits game-stats for an instance until it exits, nil when unsupported
+func (a *App) startMonitor(inst *instance.Instance) *monitor.Monitor {
+	info := inst.Info()
+	mon, err := monitor.Start(info.ID, info.PID, statsInterval, inst.Done(), func(stats monitor.Stats) {
+		runtime.EventsEmit(a.ctx, "game-stats", stats)
+	})
+	if err != nil {
+		if !errors.Is(err, monitor.ErrUnsupported) {
+			fmt.Printf("Warning: resource monitoring disabled: %v\n", err)
+		}
+		return nil
+	}
+	return mon
+}
+
+// ListRunning returns every running game and server process
+func (a *App) ListRunning() []instance.Info {
+	return a.instances.List()
+}
+
+// Stop asks a running instance to exit, it is killed if still running after the
+// stop timeout. A game waiting to be restarted is not restarted.
+func (a *App) Stop(id string) error {
+	if a.cancelRestart(id) {
+		return nil
+	}
+	if err := a.instances.Stop(id, a.stopTimeout()); err != nil {
+		return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, "Failed to stop instance", err)
+	}
+	return nil
+}
+
+// Kill terminates a running instance immediately
+func (a *App) Kill(id string) error {
+	if a.cancelRestart(id) {
+		return nil
+	}
+	if err := a.instances.Kill(id); err != nil {
+		return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, "Failed to kill instance", err)
+	}
+	return nil
+}
+
+func (a *App) stopTimeout() time.Duration {
+	seconds := a.GetSettings().StopTimeout
+	if seconds <= 0 {
+		seconds = config.Default().Settings.StopTimeout
+	}
+	return time.Duration(seconds) * time.Second
+}
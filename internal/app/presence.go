@@ -0,0 +1,140 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"HyLauncher/internal/discord"
+	"HyLauncher/internal/game"
+	"HyLauncher/internal/instance"
+)
+
+// presenceClient returns the Discord client when presence is enabled, creating it on first use
+func (a *App) presenceClient() *discord.Client {
+	a.presenceMu.Lock()
+	defer a.presenceMu.Unlock()
+
+	settings := a.GetSettings()
+	if !settings.DiscordPresence || settings.DiscordClientID == "" {
+		if a.presence != nil {
+			_ = a.presence.ClearActivity()
+			_ = a.presence.Close()
+			a.presence = nil
+		}
+		return nil
+	}
+
+	if a.presence == nil {
+		a.presence = discord.NewClient(settings.DiscordClientID, nil)
+	}
+	return a.presence
+}
+
+// Activity updates waiting for the worker, a slow Discord client only ever
+// delays the launcher's presence, never the caller
+const presenceQueueSize = 16
+
+// setPresence queues a Discord activity update, nil clears it
+func (a *App) setPresence(activity *discord.Activity) {
+	if a.presenceClient() == nil {
+		return
+	}
+
+	select {
+	case a.presenceUpdates <- activity:
+	default:
+		fmt.Println("Warning: Discord presence update dropped, too many pending")
+	}
+}
+
+// presenceWorker applies queued activity updates one at a time until shutdown.
+// Updates that piled up while Discord was slow are skipped for the newest one.
+func (a *App) presenceWorker() {
+	for {
+		var activity *discord.Activity
+		select {
+		case activity = <-a.presenceUpdates:
+		case <-a.presenceStop:
+			return
+		}
+
+	drain:
+		for {
+			select {
+			case activity = <-a.presenceUpdates:
+			default:
+				break drain
+			}
+		}
+
+		// Settings may have changed since the update was queued
+		client := a.presenceClient()
+		if client == nil {
+			continue
+		}
+		if err := client.SetActivity(activity); err != nil && !errors.Is(err, discord.ErrNotRunning) {
+			fmt.Printf("Warning: failed to update Discord presence: %v\n", err)
+		}
+	}
+}
+
+func (a *App) presenceLaunched(info instance.Info) {
+	a.setPresence(&discord.Activity{
+		Details:    "Playing Hytale",
+		State:      presenceState(info),
+		Timestamps: discord.Since(info.StartedAt),
+	})
+}
+
+func (a *App) presenceCrashed(info instance.Info) {
+	a.setPresence(&discord.Activity{
+		Details: "Game crashed",
+		State:   presenceState(info),
+	})
+}
+
+// presenceClosed clears the activity once no game is left running
+func (a *App) presenceClosed() {
+	if len(a.instances.ListKind(instance.KindGame)) > 0 {
+		return
+	}
+	a.setPresence(nil)
+}
+
+// refreshPresence reconnects after the presence settings changed and shows
+// the running game again, if any
+func (a *App) refreshPresence() {
+	a.closePresence()
+
+	if settings := a.GetSettings(); settings.DiscordPresence && settings.DiscordClientID == "" {
+		fmt.Println("Discord presence is enabled but no Discord application ID is set")
+	}
+
+	games := a.instances.ListKind(instance.KindGame)
+	if len(games) > 0 {
+		a.presenceLaunched(games[len(games)-1])
+	}
+}
+
+// closePresence clears the activity and disconnects, used on shutdown
+func (a *App) closePresence() {
+	a.presenceMu.Lock()
+	defer a.presenceMu.Unlock()
+
+	if a.presence != nil {
+		_ = a.presence.ClearActivity()
+		_ = a.presence.Close()
+		a.presence = nil
+	}
+}
+
+func presenceState(info instance.Info) string {
+	version := info.Version
+	if version == game.LatestDir {
+		if v := game.InstalledVersionOf(info.Channel, version); v > 0 {
+			version = strconv.Itoa(v)
+		}
+	}
+	return fmt.Sprintf("%s, version %s", info.ProfileName, version)
+}
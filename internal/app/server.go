@@ -0,0 +1,144 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"HyLauncher/internal/config"
+	"HyLauncher/internal/game"
+	"HyLauncher/internal/instance"
+	"HyLauncher/internal/server"
+	"HyLauncher/pkg/hyerrors"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ServerEvent is the payload of the server-started and server-stopped events
+type ServerEvent struct {
+	Instance instance.Info  `json:"instance"`
+	Port     int            `json:"port"`
+	Exit     *game.ExitInfo `json:"exit,omitempty"`
+}
+
+func (a *App) GetServerSettings() config.ServerSettings {
+	return a.configSnapshot().Server
+}
+
+func (a *App) SaveServerSettings(settings config.ServerSettings) error {
+	return a.updateConfig(func(cfg *config.Config) error {
+		cfg.Server = settings
+		return nil
+	})
+}
+
+// StartServer runs a dedicated server from the selected game installation
+func (a *App) StartServer() (instance.Info, error) {
+	channel := a.currentChannel()
+	version := a.currentVersionDir()
+
+	serverLog, err := game.NewSessionLogIn(server.LogDir(), func(line game.LogLine) {
+		runtime.EventsEmit(a.ctx, "server-log", line)
+	})
+	if err != nil {
+		fmt.Printf("Warning: server console will not be logged: %v\n", err)
+	}
+
+	srv, err := server.Start(server.Options{
+		GameDir:  game.InstallDir(channel, version),
+		Settings: a.GetServerSettings(),
+		Log:      serverLog,
+	})
+	if err != nil {
+		if serverLog != nil {
+			_ = serverLog.Close()
+		}
+		return instance.Info{}, a.handleError(hyerrors.ErrorTypeGame, "Failed to start server", err)
+	}
+
+	info := instance.Info{
+		Kind:    instance.KindServer,
+		Channel: channel,
+		Version: version,
+	}
+	if serverLog != nil {
+		info.SessionID = serverLog.ID
+	}
+	inst := a.instances.Track(srv.Cmd, info)
+
+	a.serversMu.Lock()
+	a.servers[inst.Info().ID] = srv
+	a.serversMu.Unlock()
+
+	runtime.EventsEmit(a.ctx, "server-started", ServerEvent{Instance: inst.Info(), Port: srv.Port})
+
+	go func() {
+		if err := inst.Wait(); err != nil {
+			fmt.Printf("Server process exited with error: %v\n", err)
+		}
+
+		a.serversMu.Lock()
+		delete(a.servers, inst.Info().ID)
+		a.serversMu.Unlock()
+
+		if serverLog != nil {
+			_ = serverLog.Close()
+		}
+
+		exitInfo := game.GetExitInfo(inst.ProcessState())
+		runtime.EventsEmit(a.ctx, "server-stopped", ServerEvent{Instance: inst.Info(), Port: srv.Port, Exit: &exitInfo})
+	}()
+
+	return inst.Info(), nil
+}
+
+// SendServerCommand writes a command to a running server's console
+func (a *App) SendServerCommand(id string, command string) error {
+	srv, ok := a.getServer(id)
+	if !ok {
+		return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, "Server is not running", nil)
+	}
+	if err := srv.SendCommand(command); err != nil {
+		return hyerrors.NewAppError(hyerrors.ErrorTypeGame, "Failed to send server command", err)
+	}
+	return nil
+}
+
+// StopServer asks the server to save and exit through its console, then falls
+// back to terminating the process once the stop timeout has passed
+func (a *App) StopServer(id string) error {
+	srv, ok := a.getServer(id)
+	if !ok {
+		return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, "Server is not running", nil)
+	}
+	inst, ok := a.instances.Get(id)
+	if !ok {
+		return nil
+	}
+
+	if err := srv.RequestStop(); err != nil {
+		fmt.Printf("Failed to send stop command, terminating server: %v\n", err)
+		if err := a.instances.Stop(id, a.stopTimeout()); err != nil {
+			return hyerrors.NewAppError(hyerrors.ErrorTypeGame, "Failed to stop server", err)
+		}
+		return nil
+	}
+
+	go func() {
+		select {
+		case <-inst.Done():
+		case <-time.After(a.stopTimeout()):
+			fmt.Println("Server did not stop in time, terminating it")
+			_ = a.instances.Stop(id, a.stopTimeout())
+		}
+	}()
+
+	return nil
+}
+
+func (a *App) getServer(id string) (*server.Server, bool) {
+	a.serversMu.Lock()
+	defer a.serversMu.Unlock()
+
+	srv, ok := a.servers[id]
+	return srv, ok
+}
@@ -57,6 +57,15 @@ func (a *App) DeleteGame() error {
 		}
 	}
 
+	// A custom install root may hold other files, only remove what the launcher owns
+	if installDir := env.GetInstallDir(); installDir != homeDir {
+		for _, name := range env.InstallEntries() {
+			if err := os.RemoveAll(filepath.Join(installDir, name)); err != nil {
+				deleteErrors = append(deleteErrors, name)
+			}
+		}
+	}
+
 	if len(deleteErrors) > 0 {
 		return hyerrors.NewAppError(
 			hyerrors.ErrorTypeFileSystem,
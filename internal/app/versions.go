@@ -0,0 +1,90 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+
+	"HyLauncher/internal/config"
+	"HyLauncher/internal/game"
+	"HyLauncher/pkg/hyerrors"
+)
+
+// ListInstalledVersions returns the builds installed for a channel, the current one when empty
+func (a *App) ListInstalledVersions(channel string) ([]game.InstalledVersion, error) {
+	if channel == "" {
+		channel = a.currentChannel()
+	}
+
+	activeDir := ""
+	if channel == a.currentChannel() {
+		activeDir = a.currentVersionDir()
+	}
+
+	return game.ListInstalledVersions(channel, activeDir)
+}
+
+// SetActiveVersion selects the build the next launch starts, dir is "latest" or a version number
+func (a *App) SetActiveVersion(channel string, dir string) error {
+	version := 0
+	if dir != game.LatestDir {
+		v, err := strconv.Atoi(dir)
+		if err != nil || v <= 0 {
+			return hyerrors.NewAppError(hyerrors.ErrorTypeValidation, fmt.Sprintf("Invalid game version: %s", dir), err)
+		}
+		version = v
+	}
+
+	if channel == "" {
+		channel = a.currentChannel()
+	}
+
+	return a.updateConfig(func(cfg *config.Config) error {
+		cfg.Settings.Channel = channel
+		cfg.Settings.GameVersion = version
+		return nil
+	})
+}
+
+// DeleteVersion removes an installed build that is not running
+func (a *App) DeleteVersion(channel string, dir string) error {
+	if channel == "" {
+		channel = a.currentChannel()
+	}
+
+	for _, info := range a.instances.List() {
+		if info.Channel == channel && info.Version == dir {
+			return a.handleError(hyerrors.ErrorTypeValidation, "This version is in use, stop it before deleting", nil)
+		}
+	}
+
+	if err := game.DeleteVersion(channel, dir); err != nil {
+		return a.handleError(hyerrors.ErrorTypeFileSystem, "Failed to delete version", err)
+	}
+	return nil
+}
+
+// VerifyVersion checks an installed build for missing, changed and extra files.
+// With repair set, missing and changed files are restored. Extra files are
+// only removed when listed in removeExtra, once the user confirmed them.
+func (a *App) VerifyVersion(channel string, dir string, repair bool, removeExtra []string) (*game.VerifyResult, error) {
+	if channel == "" {
+		channel = a.currentChannel()
+	}
+	if dir == "" {
+		dir = a.currentVersionDir()
+	}
+
+	if repair {
+		for _, info := range a.instances.List() {
+			if info.Channel == channel && info.Version == dir {
+				return nil, a.handleError(hyerrors.ErrorTypeValidation, "This version is in use, stop it before repairing", nil)
+			}
+		}
+	}
+
+	result, err := game.VerifyInstall(a.ctx, channel, dir, repair, removeExtra, a.GetSettings().OnlineFix, a.progress)
+	if err != nil {
+		return nil, a.handleError(hyerrors.ErrorTypeGame, "Failed to verify the installation", err)
+	}
+	return result, nil
+}
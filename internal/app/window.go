@@ -0,0 +1,57 @@
+package app
+
+import (
+	"fmt"
+
+	"HyLauncher/internal/config"
+	"HyLauncher/internal/game"
+	"HyLauncher/internal/instance"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// What the launcher window does while a game runs
+const (
+	BehaviorKeep     = "keep"
+	BehaviorHide     = "hide"
+	BehaviorMinimize = "minimize"
+	BehaviorClose    = "close"
+)
+
+// launcherBehavior returns the window behavior for a launch. Auto-restart needs the
+// launcher alive, so close falls back to hide for profiles with a restart policy.
+func (a *App) launcherBehavior(profile config.Profile) string {
+	behavior := a.GetSettings().LauncherBehavior
+	if behavior == BehaviorClose && profile.RestartPolicy != "" && profile.RestartPolicy != game.RestartNever {
+		return BehaviorHide
+	}
+	return behavior
+}
+
+// applyLauncherBehavior runs after the game started
+func (a *App) applyLauncherBehavior(behavior string) {
+	switch behavior {
+	case BehaviorHide:
+		runtime.WindowHide(a.ctx)
+	case BehaviorMinimize:
+		runtime.WindowMinimise(a.ctx)
+	case BehaviorClose:
+		// The client writes straight to its log file and has its own process group,
+		// so it keeps running once the launcher is gone
+		fmt.Println("Game started, closing the launcher")
+		runtime.Quit(a.ctx)
+	}
+}
+
+// restoreLauncher brings the window back once the last game exited
+func (a *App) restoreLauncher(behavior string) {
+	if behavior != BehaviorHide && behavior != BehaviorMinimize {
+		return
+	}
+	if len(a.instances.ListKind(instance.KindGame)) > 0 {
+		return
+	}
+
+	runtime.WindowUnminimise(a.ctx)
+	runtime.WindowShow(a.ctx)
+}
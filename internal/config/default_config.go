@@ -14,16 +14,29 @@ func Default() Config {
 		},
 		CurrentProfile: id,
 		Settings: GameSettings{
-			MinMemory:   2,
-			MaxMemory:   4,
-			Width:       1024,
-			Height:      640,
-			Fullscreen:  false,
-			JavaArgs:    "-XX:+UseG1GC -Dsun.rmi.dgc.server.gcInterval=2147483646 -XX:+UnlockExperimentalVMOptions -XX:G1NewSizePercent=20 -XX:G1ReservePercent=20 -XX:MaxGCPauseMillis=50 -XX:G1HeapRegionSize=32M",
-			GameDir:     "",
-			Channel:     "release",
-			GameVersion: 0,
-			OnlineFix:   true,
+			MinMemory:        2,
+			MaxMemory:        4,
+			JavaArgs:         "-XX:+UseG1GC -Dsun.rmi.dgc.server.gcInterval=2147483646 -XX:+UnlockExperimentalVMOptions -XX:G1NewSizePercent=20 -XX:G1ReservePercent=20 -XX:MaxGCPauseMillis=50 -XX:G1HeapRegionSize=32M",
+			GameDir:          "",
+			Channel:          "release",
+			GameVersion:      0,
+			OnlineFix:        true,
+			StopTimeout:      10,
+			LauncherBehavior: "keep",
+			PatchCacheLimit:  4096,
+		},
+		Server: ServerSettings{
+			MinMemory: 1,
+			MaxMemory: 4,
+			Port:      5520,
+			JavaArgs:  "-XX:+UseG1GC",
+		},
+		Graphics: GraphicsSettings{
+			DisplayBackend: "auto",
+			GPUOffload:     "none",
+		},
+		Butler: ButlerSettings{
+			Version: "15.21.0",
 		},
 	}
 }
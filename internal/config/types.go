@@ -1,26 +1,70 @@
 package config
 
 type Profile struct {
-	ID   string `toml:"id" json:"id"`
-	Name string `toml:"name" json:"name"`
+	ID               string            `toml:"id" json:"id"`
+	Name             string            `toml:"name" json:"name"`
+	WrapperCommand   string            `toml:"wrapper_command" json:"wrapperCommand"` // e.g. gamemoderun or "gamescope -f --"
+	PreLaunchHook    string            `toml:"pre_launch_hook" json:"preLaunchHook"`
+	PostExitHook     string            `toml:"post_exit_hook" json:"postExitHook"`
+	Env              map[string]string `toml:"env" json:"env"`
+	ExtraArgs        string            `toml:"extra_args" json:"extraArgs"`                // appended to the client arguments
+	IsolatedUserData bool              `toml:"isolated_user_data" json:"isolatedUserData"` // profiles/<id>/UserData instead of the shared UserData
+	UUIDMode         string            `toml:"uuid_mode" json:"uuidMode"`                  // profile, offline or custom
+	CustomUUID       string            `toml:"custom_uuid" json:"customUuid"`
+	Sandbox          bool              `toml:"sandbox" json:"sandbox"` // Linux only, runs the client inside bubblewrap
+	SandboxNetwork   bool              `toml:"sandbox_network" json:"sandboxNetwork"`
+	RestartPolicy    string            `toml:"restart_policy" json:"restartPolicy"` // never, on-crash or always
+	MaxRestarts      int               `toml:"max_restarts" json:"maxRestarts"`     // within RestartWindow, 0 means 3
+	RestartWindow    int               `toml:"restart_window" json:"restartWindow"` // seconds, 0 means 300
+	LastUUID         string            `toml:"last_uuid" json:"lastUuid"`           // UUID of the last launch
 }
 
 type GameSettings struct {
-	MinMemory   uint   `toml:"min_memory" json:"minMemory"`
-	MaxMemory   uint   `toml:"max_memory" json:"maxMemory"`
-	Width       int    `toml:"width" json:"width"`
-	Height      int    `toml:"height" json:"height"`
-	Fullscreen  bool   `toml:"fullscreen" json:"fullscreen"`
-	JavaArgs    string `toml:"java_args" json:"javaArgs"`
-	GameDir     string `toml:"game_dir" json:"gameDir"`
-	Channel     string `toml:"channel" json:"channel"`
-	GameVersion int    `toml:"game_version" json:"gameVersion"`
-	OnlineFix   bool   `toml:"online_fix" json:"onlineFix"`
+	MinMemory        uint   `toml:"min_memory" json:"minMemory"`
+	MaxMemory        uint   `toml:"max_memory" json:"maxMemory"`
+	Width            int    `toml:"width" json:"width"`                               // 0 keeps the client's own size
+	Height           int    `toml:"height" json:"height"`                             // 0 keeps the client's own size
+	Fullscreen       *bool  `toml:"fullscreen,omitempty" json:"fullscreen,omitempty"` // nil keeps the client's own mode
+	JavaArgs         string `toml:"java_args" json:"javaArgs"`
+	GameDir          string `toml:"game_dir" json:"gameDir"`
+	Channel          string `toml:"channel" json:"channel"`
+	GameVersion      int    `toml:"game_version" json:"gameVersion"`
+	OnlineFix        bool   `toml:"online_fix" json:"onlineFix"`
+	StopTimeout      int    `toml:"stop_timeout" json:"stopTimeout"`           // seconds before a stopped game is killed
+	LauncherBehavior string `toml:"launcher_behavior" json:"launcherBehavior"` // keep, hide, minimize or close while the game runs
+	DiscordPresence  bool   `toml:"discord_presence" json:"discordPresence"`
+	DiscordClientID  string `toml:"discord_client_id" json:"discordClientId"` // Discord application that owns the presence
+	PatchCacheLimit  int    `toml:"patch_cache_limit" json:"patchCacheLimit"` // MB, 0 keeps no patches after an install
+}
+
+type ServerSettings struct {
+	MinMemory uint   `toml:"min_memory" json:"minMemory"`
+	MaxMemory uint   `toml:"max_memory" json:"maxMemory"`
+	Port      int    `toml:"port" json:"port"`
+	JavaArgs  string `toml:"java_args" json:"javaArgs"`
+	ExtraArgs string `toml:"extra_args" json:"extraArgs"` // appended to the HytaleServer.jar arguments
+}
+
+type GraphicsSettings struct {
+	DisplayBackend string            `toml:"display_backend" json:"displayBackend"` // auto, x11 or wayland
+	GPUOffload     string            `toml:"gpu_offload" json:"gpuOffload"`         // none, mesa or nvidia
+	DRIPrime       string            `toml:"dri_prime" json:"driPrime"`             // DRI_PRIME value for mesa offload, defaults to 1
+	SDLHints       map[string]string `toml:"sdl_hints" json:"sdlHints"`             // SDL hint name (with or without SDL_) to value
+}
+
+type ButlerSettings struct {
+	Version string `toml:"version" json:"version"` // broth version installed by default
+	SHA256  string `toml:"sha256" json:"sha256"`   // expected SHA256 of the archive, or of the file in Path
+	Path    string `toml:"path" json:"path"`       // local butler binary or zip used instead of downloading
+	Native  bool   `toml:"native" json:"native"`   // try the built-in patch applier before butler
 }
 
 type Config struct {
-	Version        string       `toml:"version" json:"version"`
-	Profiles       []Profile    `toml:"profiles" json:"profiles"`
-	CurrentProfile string       `toml:"current_profile" json:"current_profile"`
-	Settings       GameSettings `toml:"settings" json:"settings"`
+	Version        string           `toml:"version" json:"version"`
+	Profiles       []Profile        `toml:"profiles" json:"profiles"`
+	CurrentProfile string           `toml:"current_profile" json:"current_profile"`
+	Settings       GameSettings     `toml:"settings" json:"settings"`
+	Server         ServerSettings   `toml:"server" json:"server"`
+	Graphics       GraphicsSettings `toml:"graphics" json:"graphics"`
+	Butler         ButlerSettings   `toml:"butler" json:"butler"`
 }
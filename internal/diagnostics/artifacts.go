@@ -0,0 +1,97 @@
+package diagnostics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"HyLauncher/pkg/fileutil"
+)
+
+// Artifacts bigger than this are listed but not copied (core dumps can be huge)
+const maxArtifactSize = 256 * 1024 * 1024
+
+// ArtifactSource is a directory to search for crash artifacts
+type ArtifactSource struct {
+	Dir      string
+	Patterns []string
+}
+
+// CrashArtifact is a file collected alongside a crash report
+type CrashArtifact struct {
+	Name    string    `json:"name"`
+	Source  string    `json:"source"`
+	Path    string    `json:"path,omitempty"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Skipped string    `json:"skipped,omitempty"`
+}
+
+// collectArtifacts copies files matching the sources and modified after since into destDir
+func collectArtifacts(sources []ArtifactSource, since time.Time, destDir string) []CrashArtifact {
+	var artifacts []CrashArtifact
+	seen := make(map[string]bool)
+
+	for _, source := range sources {
+		if source.Dir == "" {
+			continue
+		}
+
+		for _, pattern := range source.Patterns {
+			matches, err := filepath.Glob(filepath.Join(source.Dir, pattern))
+			if err != nil {
+				continue
+			}
+
+			for _, match := range matches {
+				if seen[match] {
+					continue
+				}
+				seen[match] = true
+
+				info, err := os.Stat(match)
+				if err != nil || info.IsDir() || info.ModTime().Before(since) {
+					continue
+				}
+
+				artifacts = append(artifacts, copyArtifact(match, info, destDir, len(artifacts)))
+			}
+		}
+	}
+
+	return artifacts
+}
+
+func copyArtifact(src string, info os.FileInfo, destDir string, index int) CrashArtifact {
+	artifact := CrashArtifact{
+		Name:    info.Name(),
+		Source:  src,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+
+	if info.Size() > maxArtifactSize {
+		artifact.Skipped = "file too large"
+		return artifact
+	}
+
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		artifact.Skipped = err.Error()
+		return artifact
+	}
+
+	// Different directories may hold files with the same name
+	dest := filepath.Join(destDir, info.Name())
+	if _, err := os.Stat(dest); err == nil {
+		dest = filepath.Join(destDir, fmt.Sprintf("%d_%s", index, info.Name()))
+	}
+
+	if err := fileutil.CopyFile(src, dest); err != nil {
+		artifact.Skipped = err.Error()
+		return artifact
+	}
+
+	artifact.Path = dest
+	return artifact
+}
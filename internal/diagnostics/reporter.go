@@ -23,6 +23,7 @@ type Reporter struct {
 
 // CrashReport contains all information about a crash
 type CrashReport struct {
+	ID         string             `json:"id"`
 	Timestamp  time.Time          `json:"timestamp"`
 	AppVersion string             `json:"app_version"`
 	OS         string             `json:"os"`
@@ -30,6 +31,20 @@ type CrashReport struct {
 	Error      *hyerrors.AppError `json:"error"`
 	SystemInfo SystemInfo         `json:"system_info"`
 	RecentLogs []string           `json:"recent_logs,omitempty"`
+	Game       *GameCrash         `json:"game,omitempty"`
+	Artifacts  []CrashArtifact    `json:"artifacts,omitempty"`
+}
+
+// GameCrash contains information about an abnormal exit of the game process
+type GameCrash struct {
+	SessionID       string    `json:"session_id"`
+	Channel         string    `json:"channel"`
+	Version         string    `json:"version"`
+	ExitCode        int       `json:"exit_code"`
+	Signal          string    `json:"signal,omitempty"`
+	StartedAt       time.Time `json:"started_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	LastLines       []string  `json:"last_lines,omitempty"`
 }
 
 // SystemInfo contains system information
@@ -109,8 +124,51 @@ func (r *Reporter) logError(err *hyerrors.AppError) {
 
 // saveCrashReport saves a crash report to disk
 func (r *Reporter) saveCrashReport(err *hyerrors.AppError) error {
-	report := CrashReport{
-		Timestamp:  time.Now(),
+	report := r.newCrashReport(err)
+
+	// Try to read recent logs
+	logFile := filepath.Join(r.logDir, "errors.log")
+	if logData, readErr := os.ReadFile(logFile); readErr == nil {
+		// Get last 5000 characters
+		lines := string(logData)
+		if len(lines) > 5000 {
+			lines = lines[len(lines)-5000:]
+		}
+		report.RecentLogs = []string{lines}
+	}
+
+	return r.writeCrashReport(&report)
+}
+
+// SaveGameCrash saves a crash report for a game process that exited abnormally.
+// Unlike launcher errors it is not passed through the global error handler.
+// Artifacts found in sources since the session started are copied to crashes/<id>/.
+func (r *Reporter) SaveGameCrash(crash GameCrash, sources []ArtifactSource) (*CrashReport, error) {
+	message := fmt.Sprintf("Game exited with code %d", crash.ExitCode)
+	if crash.Signal != "" {
+		message = fmt.Sprintf("Game was terminated by signal: %s", crash.Signal)
+	}
+
+	report := r.newCrashReport(&hyerrors.AppError{
+		Type:      hyerrors.ErrorTypeGame,
+		Message:   message,
+		Technical: fmt.Sprintf("session %s, ran for %.0fs", crash.SessionID, crash.DurationSeconds),
+		Timestamp: time.Now(),
+	})
+	report.Game = &crash
+	report.Artifacts = collectArtifacts(sources, crash.StartedAt, filepath.Join(r.crashDir, report.ID))
+
+	if err := r.writeCrashReport(&report); err != nil {
+		return &report, err
+	}
+	return &report, nil
+}
+
+func (r *Reporter) newCrashReport(err *hyerrors.AppError) CrashReport {
+	now := time.Now()
+	return CrashReport{
+		ID:         r.newCrashID(now),
+		Timestamp:  now,
 		AppVersion: r.version,
 		OS:         runtime.GOOS,
 		Arch:       runtime.GOARCH,
@@ -123,18 +181,31 @@ func (r *Reporter) saveCrashReport(err *hyerrors.AppError) error {
 			NumGoroutine: runtime.NumGoroutine(),
 		},
 	}
+}
 
-	// Try to read recent logs
-	logFile := filepath.Join(r.logDir, "errors.log")
-	if logData, readErr := os.ReadFile(logFile); readErr == nil {
-		// Get last 5000 characters
-		lines := string(logData)
-		if len(lines) > 5000 {
-			lines = lines[len(lines)-5000:]
+// newCrashID names a report after its time. A crash loop can report several
+// crashes within a millisecond, so the ID is reserved by creating its file and
+// an ID already on disk gets a counter.
+func (r *Reporter) newCrashID(now time.Time) string {
+	base := "crash_" + now.Format("2006-01-02_15-04-05.000")
+	id := base
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(filepath.Join(r.crashDir, id)); os.IsNotExist(err) {
+			f, err := os.OpenFile(filepath.Join(r.crashDir, id+".json"), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+			if err == nil {
+				f.Close()
+				return id
+			}
+			if !os.IsExist(err) {
+				// Writing the report will fail and say why
+				return id
+			}
 		}
-		report.RecentLogs = []string{lines}
+		id = fmt.Sprintf("%s-%d", base, i)
 	}
+}
 
+func (r *Reporter) writeCrashReport(report *CrashReport) error {
 	// Marshal to JSON
 	data, marshalErr := json.MarshalIndent(report, "", "  ")
 	if marshalErr != nil {
@@ -142,13 +213,12 @@ func (r *Reporter) saveCrashReport(err *hyerrors.AppError) error {
 	}
 
 	// Save to file
-	filename := fmt.Sprintf("crash_%s.json", time.Now().Format("2006-01-02_15-04-05"))
-	crashFile := filepath.Join(r.crashDir, filename)
+	crashFile := filepath.Join(r.crashDir, report.ID+".json")
 
 	return os.WriteFile(crashFile, data, 0644)
 }
 
-// ClearOldCrashReports removes crash reports older than 30 days
+// ClearOldCrashReports removes crash reports and their artifacts older than 30 days
 func (r *Reporter) ClearOldCrashReports() error {
 	entries, err := os.ReadDir(r.crashDir)
 	if err != nil {
@@ -161,10 +231,6 @@ func (r *Reporter) ClearOldCrashReports() error {
 	thirtyDaysAgo := time.Now().AddDate(0, 0, -30)
 
 	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
 		info, err := entry.Info()
 		if err != nil {
 			continue
@@ -172,7 +238,7 @@ func (r *Reporter) ClearOldCrashReports() error {
 
 		if info.ModTime().Before(thirtyDaysAgo) {
 			filePath := filepath.Join(r.crashDir, entry.Name())
-			_ = os.Remove(filePath)
+			_ = os.RemoveAll(filePath)
 		}
 	}
 
@@ -0,0 +1,39 @@
+package diagnostics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewCrashIDUnique(t *testing.T) {
+	r := &Reporter{crashDir: t.TempDir()}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 6e6, time.UTC)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 5; i++ {
+		id := r.newCrashID(now)
+		if seen[id] {
+			t.Fatalf("crash ID %s handed out twice", id)
+		}
+		seen[id] = true
+	}
+
+	if !seen["crash_2026-01-02_03-04-05.006"] || !seen["crash_2026-01-02_03-04-05.006-5"] {
+		t.Errorf("unexpected IDs %v", seen)
+	}
+}
+
+func TestNewCrashIDSkipsArtifactDir(t *testing.T) {
+	r := &Reporter{crashDir: t.TempDir()}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if err := os.Mkdir(filepath.Join(r.crashDir, "crash_2026-01-02_03-04-05.000"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if id := r.newCrashID(now); id != "crash_2026-01-02_03-04-05.000-2" {
+		t.Errorf("newCrashID = %s", id)
+	}
+}
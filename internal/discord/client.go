@@ -0,0 +1,200 @@
+package discord
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const ioTimeout = 5 * time.Second
+
+// ErrNotRunning is returned when no Discord client accepts the connection
+var ErrNotRunning = errors.New("discord is not running")
+
+// Dialer opens a connection to the Discord client, DialIPC by default.
+// Tests can point it at a fake server.
+type Dialer func() (io.ReadWriteCloser, error)
+
+// Activity is the rich presence shown on the user's profile
+type Activity struct {
+	Details    string      `json:"details,omitempty"`
+	State      string      `json:"state,omitempty"`
+	Timestamps *Timestamps `json:"timestamps,omitempty"`
+	Assets     *Assets     `json:"assets,omitempty"`
+}
+
+// Timestamps makes Discord show the elapsed time
+type Timestamps struct {
+	Start int64 `json:"start,omitempty"` // unix seconds
+}
+
+// Assets are images uploaded to the Discord application
+type Assets struct {
+	LargeImage string `json:"large_image,omitempty"`
+	LargeText  string `json:"large_text,omitempty"`
+}
+
+// Client keeps one IPC connection to the local Discord client and reconnects on demand
+type Client struct {
+	clientID string
+	dial     Dialer
+
+	mu   sync.Mutex
+	conn io.ReadWriteCloser
+}
+
+type message struct {
+	Cmd   string          `json:"cmd"`
+	Evt   string          `json:"evt"`
+	Nonce string          `json:"nonce"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// NewClient creates a client for a Discord application, nothing is dialed until
+// the first activity update
+func NewClient(clientID string, dial Dialer) *Client {
+	if dial == nil {
+		dial = DialIPC
+	}
+	return &Client{clientID: clientID, dial: dial}
+}
+
+// Since returns timestamps counting from t
+func Since(t time.Time) *Timestamps {
+	return &Timestamps{Start: t.Unix()}
+}
+
+// SetActivity replaces the presence, a nil activity clears it
+func (c *Client) SetActivity(activity *Activity) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.setActivity(activity)
+	if err != nil && c.conn != nil {
+		// Discord may have restarted since the last update, retry on a new connection
+		c.closeConn()
+		err = c.setActivity(activity)
+	}
+	return err
+}
+
+// ClearActivity removes the presence
+func (c *Client) ClearActivity() error {
+	return c.SetActivity(nil)
+}
+
+// Close drops the connection, Discord also removes the presence it set
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeConn()
+}
+
+func (c *Client) setActivity(activity *Activity) error {
+	if err := c.connect(); err != nil {
+		return err
+	}
+	c.setDeadline()
+
+	args := map[string]any{
+		"pid":      os.Getpid(),
+		"activity": activity,
+	}
+	nonce := uuid.New().String()
+	if err := writeFrame(c.conn, opFrame, map[string]any{
+		"cmd":   "SET_ACTIVITY",
+		"args":  args,
+		"nonce": nonce,
+	}); err != nil {
+		return err
+	}
+
+	reply, err := c.read()
+	if err != nil {
+		return err
+	}
+	if reply.Evt == "ERROR" {
+		return fmt.Errorf("discord rejected the activity: %s", reply.Data)
+	}
+	return nil
+}
+
+// connect dials and performs the handshake, Discord answers with a READY event
+func (c *Client) connect() error {
+	if c.conn != nil {
+		return nil
+	}
+	if c.clientID == "" {
+		return fmt.Errorf("no Discord application ID configured")
+	}
+
+	conn, err := c.dial()
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	c.setDeadline()
+
+	if err := writeFrame(conn, opHandshake, map[string]any{"v": 1, "client_id": c.clientID}); err != nil {
+		c.closeConn()
+		return err
+	}
+
+	ready, err := c.read()
+	if err != nil {
+		c.closeConn()
+		return fmt.Errorf("discord handshake failed: %w", err)
+	}
+	if ready.Evt != "READY" {
+		c.closeConn()
+		return fmt.Errorf("discord handshake failed: %s", ready.Data)
+	}
+	return nil
+}
+
+// read returns the next command reply, answering pings on the way
+func (c *Client) read() (message, error) {
+	for {
+		op, payload, err := readFrame(c.conn)
+		if err != nil {
+			return message{}, err
+		}
+
+		switch op {
+		case opPing:
+			if err := writeFrame(c.conn, opPong, json.RawMessage(payload)); err != nil {
+				return message{}, err
+			}
+		case opClose:
+			return message{}, fmt.Errorf("discord closed the connection: %s", payload)
+		case opFrame:
+			var msg message
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				return message{}, err
+			}
+			return msg, nil
+		}
+	}
+}
+
+// setDeadline keeps a stuck Discord client from blocking updates forever
+func (c *Client) setDeadline() {
+	if conn, ok := c.conn.(interface{ SetDeadline(time.Time) error }); ok {
+		_ = conn.SetDeadline(time.Now().Add(ioTimeout))
+	}
+}
+
+func (c *Client) closeConn() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
@@ -0,0 +1,189 @@
+package discord
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeDiscord hands out one end of a pipe per dial and serves the other end
+type fakeDiscord struct {
+	t     *testing.T
+	conns chan net.Conn
+}
+
+func newFakeDiscord(t *testing.T) *fakeDiscord {
+	return &fakeDiscord{t: t, conns: make(chan net.Conn, 4)}
+}
+
+func (f *fakeDiscord) dial() (io.ReadWriteCloser, error) {
+	client, server := net.Pipe()
+	f.conns <- server
+	return client, nil
+}
+
+// accept returns the server end of the next dialed connection
+func (f *fakeDiscord) accept() net.Conn {
+	f.t.Helper()
+	select {
+	case conn := <-f.conns:
+		_ = conn.SetDeadline(time.Now().Add(ioTimeout))
+		return conn
+	case <-time.After(ioTimeout):
+		f.t.Fatal("client did not dial")
+		return nil
+	}
+}
+
+// expect reads a frame and checks its opcode
+func (f *fakeDiscord) expect(conn net.Conn, op uint32) map[string]any {
+	f.t.Helper()
+	gotOp, payload, err := readFrame(conn)
+	if err != nil {
+		f.t.Fatalf("reading frame: %v", err)
+	}
+	if gotOp != op {
+		f.t.Fatalf("opcode = %d, want %d: %s", gotOp, op, payload)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(payload, &body); err != nil {
+		f.t.Fatalf("frame is not JSON: %s", payload)
+	}
+	return body
+}
+
+func (f *fakeDiscord) reply(conn net.Conn, evt string) {
+	f.t.Helper()
+	if err := writeFrame(conn, opFrame, map[string]any{"evt": evt, "data": map[string]any{}}); err != nil {
+		f.t.Fatalf("writing reply: %v", err)
+	}
+}
+
+// handshake checks the handshake frame and answers READY
+func (f *fakeDiscord) handshake(conn net.Conn) {
+	f.t.Helper()
+	body := f.expect(conn, opHandshake)
+	if body["client_id"] != "1234" || body["v"] != float64(1) {
+		f.t.Errorf("handshake = %v", body)
+	}
+	f.reply(conn, "READY")
+}
+
+// activity checks a SET_ACTIVITY frame, answers it and returns the activity sent
+func (f *fakeDiscord) activity(conn net.Conn) any {
+	f.t.Helper()
+	body := f.expect(conn, opFrame)
+	if body["cmd"] != "SET_ACTIVITY" || body["nonce"] == "" {
+		f.t.Errorf("command = %v", body)
+	}
+	args, _ := body["args"].(map[string]any)
+	if _, ok := args["pid"].(float64); !ok {
+		f.t.Errorf("args = %v, missing pid", args)
+	}
+	f.reply(conn, "")
+	return args["activity"]
+}
+
+// serve runs fn against the server side and waits for it together with the client call
+func serve(t *testing.T, fn func(), call func() error) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	if err := call(); err != nil {
+		t.Errorf("client: %v", err)
+	}
+	<-done
+}
+
+func TestClientActivity(t *testing.T) {
+	fake := newFakeDiscord(t)
+	client := NewClient("1234", fake.dial)
+	defer client.Close()
+
+	var conn net.Conn
+	serve(t, func() {
+		conn = fake.accept()
+		fake.handshake(conn)
+		got := fake.activity(conn)
+		activity, _ := got.(map[string]any)
+		if activity["details"] != "Playing Hytale" || activity["state"] != "Default, version 5" {
+			t.Errorf("activity = %v", got)
+		}
+		timestamps, _ := activity["timestamps"].(map[string]any)
+		if timestamps["start"] != float64(1700000000) {
+			t.Errorf("timestamps = %v", activity["timestamps"])
+		}
+	}, func() error {
+		return client.SetActivity(&Activity{
+			Details:    "Playing Hytale",
+			State:      "Default, version 5",
+			Timestamps: Since(time.Unix(1700000000, 0)),
+		})
+	})
+
+	// Clearing reuses the connection and sends a null activity
+	serve(t, func() {
+		if got := fake.activity(conn); got != nil {
+			t.Errorf("clear sent activity %v, want null", got)
+		}
+	}, client.ClearActivity)
+}
+
+func TestClientReconnect(t *testing.T) {
+	fake := newFakeDiscord(t)
+	client := NewClient("1234", fake.dial)
+	defer client.Close()
+
+	serve(t, func() {
+		conn := fake.accept()
+		fake.handshake(conn)
+		fake.activity(conn)
+		// Discord restarts
+		conn.Close()
+	}, func() error {
+		return client.SetActivity(&Activity{Details: "first"})
+	})
+
+	serve(t, func() {
+		conn := fake.accept()
+		fake.handshake(conn)
+		got, _ := fake.activity(conn).(map[string]any)
+		if got["details"] != "second" {
+			t.Errorf("activity after reconnect = %v", got)
+		}
+	}, func() error {
+		return client.SetActivity(&Activity{Details: "second"})
+	})
+}
+
+func TestClientRejected(t *testing.T) {
+	fake := newFakeDiscord(t)
+	client := NewClient("1234", fake.dial)
+	defer client.Close()
+
+	serve(t, func() {
+		conn := fake.accept()
+		fake.expect(conn, opHandshake)
+		writeFrame(conn, opClose, map[string]any{"code": 4000, "message": "Invalid Client ID"})
+	}, func() error {
+		if err := client.SetActivity(&Activity{}); err == nil {
+			t.Error("handshake close was not reported")
+		}
+		return nil
+	})
+}
+
+func TestClientNotRunning(t *testing.T) {
+	client := NewClient("1234", func() (io.ReadWriteCloser, error) {
+		return nil, ErrNotRunning
+	})
+	if err := client.SetActivity(&Activity{}); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("SetActivity() error = %v, want ErrNotRunning", err)
+	}
+}
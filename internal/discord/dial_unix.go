@@ -0,0 +1,41 @@
+//go:build !windows
+
+package discord
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Sandboxed Discord installs put the socket in a subdirectory of the runtime dir
+var socketSubdirs = []string{"", "app/com.discordapp.Discord", "snap.discord"}
+
+// DialIPC connects to the first discord-ipc-N socket that accepts
+func DialIPC() (io.ReadWriteCloser, error) {
+	for _, dir := range socketDirs() {
+		for _, sub := range socketSubdirs {
+			for i := 0; i < 10; i++ {
+				path := filepath.Join(dir, sub, fmt.Sprintf("discord-ipc-%d", i))
+				conn, err := net.DialTimeout("unix", path, time.Second)
+				if err == nil {
+					return conn, nil
+				}
+			}
+		}
+	}
+	return nil, ErrNotRunning
+}
+
+func socketDirs() []string {
+	var dirs []string
+	for _, key := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP", "TEMP"} {
+		if dir := os.Getenv(key); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return append(dirs, "/tmp")
+}
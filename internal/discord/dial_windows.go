@@ -0,0 +1,20 @@
+//go:build windows
+
+package discord
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// DialIPC connects to the first \\.\pipe\discord-ipc-N pipe that accepts
+func DialIPC() (io.ReadWriteCloser, error) {
+	for i := 0; i < 10; i++ {
+		pipe, err := os.OpenFile(fmt.Sprintf(`\\.\pipe\discord-ipc-%d`, i), os.O_RDWR, 0)
+		if err == nil {
+			return pipe, nil
+		}
+	}
+	return nil, ErrNotRunning
+}
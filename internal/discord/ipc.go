@@ -0,0 +1,56 @@
+package discord
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// IPC opcodes
+const (
+	opHandshake uint32 = 0
+	opFrame     uint32 = 1
+	opClose     uint32 = 2
+	opPing      uint32 = 3
+	opPong      uint32 = 4
+)
+
+// Frames larger than this are not from Discord
+const maxFrameSize = 64 * 1024
+
+// writeFrame sends a payload with its 8 byte header: opcode and length, little endian
+func writeFrame(w io.Writer, op uint32, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	frame := make([]byte, 8+len(data))
+	binary.LittleEndian.PutUint32(frame[0:4], op)
+	binary.LittleEndian.PutUint32(frame[4:8], uint32(len(data)))
+	copy(frame[8:], data)
+
+	_, err = w.Write(frame)
+	return err
+}
+
+// readFrame reads one frame and returns its opcode and raw payload
+func readFrame(r io.Reader) (uint32, []byte, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	op := binary.LittleEndian.Uint32(header[0:4])
+	size := binary.LittleEndian.Uint32(header[4:8])
+	if size > maxFrameSize {
+		return 0, nil, fmt.Errorf("discord frame too large: %d bytes", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return op, payload, nil
+}
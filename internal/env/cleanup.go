@@ -4,17 +4,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 func CleanupLauncher() error {
-	appDir := GetDefaultAppDir()
-	cacheDir := filepath.Join(appDir, "cache")
+	cacheDir := GetCacheDir()
 
-	if err := cleanDirectory(cacheDir, []string{".pwr", ".zip", ".tar.gz"}); err != nil {
+	// Patches in the top level predate the keyed cache in cache/pwr, nothing
+	// records which build they apply to
+	if err := cleanDirectory(cacheDir, []string{".pwr", ".tmp", ".zip", ".tar.gz"}); err != nil {
 		fmt.Println("Warning: failed to clean cache:", err)
 	}
 
-	gameLatest := filepath.Join(appDir, "release", "package", "game", "latest")
+	gameLatest := GetGameDir("release", "latest")
 	if err := cleanIncompleteGame(gameLatest); err != nil {
 		fmt.Println("Warning: failed to clean game directory:", err)
 	}
@@ -71,7 +73,7 @@ func cleanDirectory(dir string, extensions []string) error {
 		}
 
 		for _, ext := range extensions {
-			if filepath.Ext(entry.Name()) == ext {
+			if strings.HasSuffix(entry.Name(), ext) {
 				filePath := filepath.Join(dir, entry.Name())
 				fmt.Println("Removing incomplete download:", filePath)
 				if err := os.Remove(filePath); err != nil {
@@ -45,21 +45,20 @@ func GetDefaultAppDir() string {
 }
 
 func GetCacheDir() string {
-	return filepath.Join(GetDefaultAppDir(), "cache")
+	return filepath.Join(GetInstallDir(), "cache")
 }
 
 func CreateFolders() error {
 	basePath := GetDefaultAppDir()
-	packagePath := filepath.Join(basePath, "release", "package") // Package folder
 
 	paths := []string{
-		basePath,                                   // main folder
-		filepath.Join(basePath, "UserData"),        // UserData
-		filepath.Join(basePath, "cache"),           // cache Folder
-		filepath.Join(basePath, "tools", "butler"), // Butler
-		filepath.Join(packagePath, "jre"),          // JRE Folder
-		filepath.Join(packagePath, "game"),
-		filepath.Join(packagePath, "game", "latest"),
+		basePath,                            // main folder
+		filepath.Join(basePath, "UserData"), // UserData
+		GetCacheDir(),                       // cache Folder
+		GetButlerDir(),                      // Butler
+		GetJREDir(),                         // JRE Folder
+		filepath.Dir(GetGameDir("release", "latest")),
+		GetGameDir("release", "latest"),
 	}
 
 	for _, p := range paths {
@@ -0,0 +1,69 @@
+package env
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+// Channels are the patch channels that can have an installation
+var Channels = []string{"release", "pre-release"}
+
+var (
+	installDirMu sync.RWMutex
+	installDir   string
+)
+
+// SetInstallDir sets a custom root for game installations, the JRE and the cache.
+// An empty dir restores the default app directory.
+func SetInstallDir(dir string) {
+	if dir != "" {
+		dir = filepath.Clean(dir)
+	}
+
+	installDirMu.Lock()
+	installDir = dir
+	installDirMu.Unlock()
+}
+
+// GetInstallDir returns the root holding game installations, the JRE and the cache.
+// Config, logs, tools and UserData always stay in the default app directory.
+func GetInstallDir() string {
+	installDirMu.RLock()
+	dir := installDir
+	installDirMu.RUnlock()
+
+	if dir == "" {
+		return GetDefaultAppDir()
+	}
+	return dir
+}
+
+// InstallEntries lists the top level entries of the install root owned by the launcher
+func InstallEntries() []string {
+	return append([]string{"cache"}, Channels...)
+}
+
+// GetChannelDir returns the directory of a patch channel
+func GetChannelDir(channel string) string {
+	return filepath.Join(GetInstallDir(), channel)
+}
+
+// GetGameDir returns the install directory of a game version
+func GetGameDir(channel, version string) string {
+	return filepath.Join(GetChannelDir(channel), "package", "game", version)
+}
+
+// GetJREDir returns the directory holding the bundled JRE
+func GetJREDir() string {
+	return filepath.Join(GetChannelDir("release"), "package", "jre")
+}
+
+// GetButlerDir returns the directory holding the butler tool
+func GetButlerDir() string {
+	return filepath.Join(GetDefaultAppDir(), "tools", "butler")
+}
+
+// GetPatchCacheDir returns the directory holding cached .pwr patches and their index
+func GetPatchCacheDir() string {
+	return filepath.Join(GetCacheDir(), "pwr")
+}
@@ -0,0 +1,224 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"HyLauncher/internal/config"
+)
+
+// The java launcher prepends this variable to its own arguments, so options set
+// here reach the JVM the client starts through --java-exec
+const jvmOptionsEnv = "JDK_JAVA_OPTIONS"
+
+// Client settings file inside the user directory, read by the client on startup
+const clientSettingsFile = "Settings.json"
+
+// LaunchSettings contains everything derived from GameSettings for a single launch,
+// along with the directories the launch resolved to
+type LaunchSettings struct {
+	JVMOptions  []string `json:"jvmOptions"`
+	Env         []string `json:"env"`
+	Applied     []string `json:"applied"`
+	GameDir     string   `json:"gameDir"`
+	UserDataDir string   `json:"userDataDir"`
+}
+
+// BuildLaunchSettings turns the stored game settings into JVM options and environment
+func BuildLaunchSettings(settings config.GameSettings) (*LaunchSettings, error) {
+	ls := &LaunchSettings{}
+
+	minMem := settings.MinMemory
+	maxMem := settings.MaxMemory
+	if maxMem > 0 && minMem > maxMem {
+		minMem = maxMem
+		ls.Applied = append(ls.Applied, fmt.Sprintf("Min memory lowered to %dG to match max memory", maxMem))
+	}
+
+	if minMem > 0 {
+		ls.JVMOptions = append(ls.JVMOptions, fmt.Sprintf("-Xms%dG", minMem))
+	}
+	if maxMem > 0 {
+		ls.JVMOptions = append(ls.JVMOptions, fmt.Sprintf("-Xmx%dG", maxMem))
+	}
+	if minMem > 0 || maxMem > 0 {
+		ls.Applied = append(ls.Applied, fmt.Sprintf("Memory: %s - %s", formatMemory(minMem), formatMemory(maxMem)))
+	}
+
+	extra, err := SplitArgs(settings.JavaArgs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Java arguments: %w", err)
+	}
+	for _, arg := range extra {
+		if isHeapOption(arg) && (minMem > 0 || maxMem > 0) {
+			// Memory is managed by the min/max memory settings
+			ls.Applied = append(ls.Applied, fmt.Sprintf("Ignored Java argument %s (use memory settings)", arg))
+			continue
+		}
+		ls.JVMOptions = append(ls.JVMOptions, arg)
+	}
+	if len(extra) > 0 {
+		ls.Applied = append(ls.Applied, fmt.Sprintf("Java arguments: %d options", len(extra)))
+	}
+
+	if len(ls.JVMOptions) > 0 {
+		value := JoinArgs(ls.JVMOptions)
+		// Keep options the user already exported, ours come first so theirs win
+		if existing := os.Getenv(jvmOptionsEnv); existing != "" {
+			value += " " + existing
+		}
+		ls.Env = append(ls.Env, jvmOptionsEnv+"="+value)
+	}
+
+	if settings.Fullscreen != nil {
+		if *settings.Fullscreen {
+			ls.Applied = append(ls.Applied, "Display: fullscreen")
+		} else {
+			ls.Applied = append(ls.Applied, "Display: windowed")
+		}
+	}
+	if settings.Width > 0 && settings.Height > 0 {
+		ls.Applied = append(ls.Applied, fmt.Sprintf("Display: %dx%d", settings.Width, settings.Height))
+	}
+
+	return ls, nil
+}
+
+// Display keys of the client settings file. They are only updated when the
+// client already wrote them, so the file never gains keys the client does not define.
+const (
+	clientFullscreenKey   = "Fullscreen"
+	clientWindowWidthKey  = "WindowWidth"
+	clientWindowHeightKey = "WindowHeight"
+)
+
+// applyClientSettings updates the display keys the settings set explicitly in the
+// client settings file, keeping every other key as it is. It returns the keys that
+// were left alone because the client has not defined them yet.
+func applyClientSettings(userDataDir string, settings config.GameSettings) ([]string, error) {
+	wanted := make(map[string]any)
+	if settings.Fullscreen != nil {
+		wanted[clientFullscreenKey] = *settings.Fullscreen
+	}
+	if settings.Width > 0 && settings.Height > 0 {
+		wanted[clientWindowWidthKey] = settings.Width
+		wanted[clientWindowHeightKey] = settings.Height
+	}
+	if len(wanted) == 0 {
+		return nil, nil
+	}
+
+	path := filepath.Join(userDataDir, clientSettingsFile)
+
+	values := make(map[string]json.RawMessage)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("client settings file is not valid JSON: %w", err)
+		}
+	}
+
+	var skipped []string
+	changed := false
+	for key, value := range wanted {
+		if _, ok := values[key]; !ok {
+			skipped = append(skipped, key)
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = raw
+		changed = true
+	}
+	sort.Strings(skipped)
+
+	if !changed {
+		return skipped, nil
+	}
+
+	data, err = json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return skipped, os.WriteFile(path, data, 0644)
+}
+
+// SplitArgs splits a command line into arguments, honoring single and double quotes.
+// A backslash only escapes quotes, whitespace and itself so Windows paths stay intact.
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && quote != '\'' && i+1 < len(runes) && strings.ContainsRune("\"'\\ \t", runes[i+1]):
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+// JoinArgs joins arguments back into a single line, quoting where needed
+func JoinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'\\") {
+			arg = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+func isHeapOption(arg string) bool {
+	return strings.HasPrefix(arg, "-Xms") || strings.HasPrefix(arg, "-Xmx")
+}
+
+func formatMemory(gb uint) string {
+	if gb == 0 {
+		return "default"
+	}
+	return fmt.Sprintf("%dG", gb)
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"HyLauncher/internal/config"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", in: "", want: nil},
+		{name: "whitespace only", in: " \t\n ", want: nil},
+		{name: "plain", in: "-Xmx4G -XX:+UseG1GC", want: []string{"-Xmx4G", "-XX:+UseG1GC"}},
+		{name: "repeated spaces", in: "  a   b\tc ", want: []string{"a", "b", "c"}},
+		{name: "double quotes", in: `-Dname="hello world" x`, want: []string{"-Dname=hello world", "x"}},
+		{name: "single quotes", in: `'a b' c`, want: []string{"a b", "c"}},
+		{name: "empty quotes", in: `"" x`, want: []string{"", "x"}},
+		{name: "escaped quote", in: `a\"b`, want: []string{`a"b`}},
+		{name: "escaped space", in: `a\ b c`, want: []string{"a b", "c"}},
+		{name: "escaped backslash", in: `a\\b`, want: []string{`a\b`}},
+		{name: "windows path", in: `-Dpath=C:\Games\Hytale`, want: []string{`-Dpath=C:\Games\Hytale`}},
+		{name: "backslash in single quotes", in: `'a\"b'`, want: []string{`a\"b`}},
+		{name: "escaped quote in double quotes", in: `"a \"b\""`, want: []string{`a "b"`}},
+		{name: "trailing backslash", in: `a\`, want: []string{`a\`}},
+		{name: "unterminated double quote", in: `"abc`, wantErr: true},
+		{name: "unterminated single quote", in: `'abc`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitArgs(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitArgs(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want string
+	}{
+		{name: "plain", in: []string{"-Xmx4G", "-XX:+UseG1GC"}, want: "-Xmx4G -XX:+UseG1GC"},
+		{name: "space", in: []string{"a b"}, want: `"a b"`},
+		{name: "empty", in: []string{""}, want: `""`},
+		{name: "quote", in: []string{`a"b`}, want: `"a\"b"`},
+		{name: "backslash", in: []string{`C:\Games`}, want: `"C:\\Games"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JoinArgs(tt.in); got != tt.want {
+				t.Errorf("JoinArgs(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinArgsRoundTrip(t *testing.T) {
+	args := []string{"plain", "with space", "", `quote"d`, `single'`, `C:\Program Files\Java`, `trailing\`, "tab\there"}
+
+	got, err := SplitArgs(JoinArgs(args))
+	if err != nil {
+		t.Fatalf("SplitArgs: %v", err)
+	}
+	if !reflect.DeepEqual(got, args) {
+		t.Errorf("round trip = %q, want %q", got, args)
+	}
+}
+
+func TestApplyClientSettings(t *testing.T) {
+	on := true
+
+	tests := []struct {
+		name        string
+		existing    string // empty for no file
+		settings    config.GameSettings
+		want        map[string]any // nil when the file must be left alone
+		wantSkipped []string
+	}{
+		{
+			name:     "nothing set",
+			existing: `{"Fullscreen": false, "Volume": 3}`,
+			settings: config.GameSettings{},
+		},
+		{
+			name:     "only defined keys",
+			existing: `{"Fullscreen": false, "WindowWidth": 800, "WindowHeight": 600, "Volume": 3}`,
+			settings: config.GameSettings{Fullscreen: &on, Width: 1920, Height: 1080},
+			want:     map[string]any{"Fullscreen": true, "WindowWidth": 1920.0, "WindowHeight": 1080.0, "Volume": 3.0},
+		},
+		{
+			name:        "undefined keys are skipped",
+			existing:    `{"Fullscreen": false, "Volume": 3}`,
+			settings:    config.GameSettings{Fullscreen: &on, Width: 1920, Height: 1080},
+			want:        map[string]any{"Fullscreen": true, "Volume": 3.0},
+			wantSkipped: []string{"WindowHeight", "WindowWidth"},
+		},
+		{
+			name:     "width without height",
+			existing: `{"WindowWidth": 800, "WindowHeight": 600}`,
+			settings: config.GameSettings{Width: 1920},
+		},
+		{
+			name:        "no file",
+			settings:    config.GameSettings{Fullscreen: &on},
+			wantSkipped: []string{"Fullscreen"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, clientSettingsFile)
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			skipped, err := applyClientSettings(dir, tt.settings)
+			if err != nil {
+				t.Fatalf("applyClientSettings: %v", err)
+			}
+			if !reflect.DeepEqual(skipped, tt.wantSkipped) {
+				t.Errorf("skipped = %q, want %q", skipped, tt.wantSkipped)
+			}
+
+			data, err := os.ReadFile(path)
+			if tt.want == nil {
+				if tt.existing == "" && err == nil {
+					t.Fatalf("settings file was created")
+				}
+				if tt.existing != "" && string(data) != tt.existing {
+					t.Errorf("settings file changed to %s", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got map[string]any
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("settings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyClientSettingsInvalidJSON(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, clientSettingsFile), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	on := true
+	if _, err := applyClientSettings(dir, config.GameSettings{Fullscreen: &on}); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,28 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+
+	"HyLauncher/internal/diagnostics"
+)
+
+// CrashArtifactSources lists where the JVM and the client leave crash artifacts
+func CrashArtifactSources(ls *LaunchSettings) []diagnostics.ArtifactSource {
+	jvmPatterns := []string{"hs_err_pid*.log", "replay_pid*.log", "*.dmp"}
+
+	sources := []diagnostics.ArtifactSource{
+		{Dir: ls.GameDir, Patterns: jvmPatterns},
+		{Dir: filepath.Join(ls.GameDir, "Client"), Patterns: jvmPatterns},
+		{Dir: filepath.Join(ls.GameDir, "Server"), Patterns: jvmPatterns},
+		{Dir: ls.UserDataDir, Patterns: jvmPatterns},
+		{Dir: filepath.Join(ls.UserDataDir, "Logs"), Patterns: []string{"*.log", "*.txt"}},
+	}
+
+	// The client inherits the launcher's working directory, so the JVM may write there too
+	if cwd, err := os.Getwd(); err == nil {
+		sources = append(sources, diagnostics.ArtifactSource{Dir: cwd, Patterns: jvmPatterns})
+	}
+
+	return sources
+}
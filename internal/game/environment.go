@@ -0,0 +1,187 @@
+package game
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	"HyLauncher/internal/config"
+)
+
+// Variables a profile may not set: they are managed by the launcher or would
+// move the client's data somewhere else. Names ending in * are prefixes.
+var blockedEnvVars = []string{
+	jvmOptionsEnv,
+	"HYLAUNCHER_*",
+	"HOME",
+	"USERPROFILE",
+	"APPDATA",
+	"LOCALAPPDATA",
+	"XDG_DATA_HOME",
+	"XDG_CONFIG_HOME",
+}
+
+// Client flags set by the launcher that extra arguments may not repeat
+var managedClientFlags = []string{
+	"--app-dir",
+	"--user-dir",
+	"--java-exec",
+	"--auth-mode",
+	"--uuid",
+	"--name",
+}
+
+// envBuilder merges environment layers, later values override earlier ones.
+// The client environment is built in this order:
+//  1. the launcher's own environment
+//  2. graphics settings (display backend, GPU offload, SDL hints)
+//  3. profile variables, minus the blocklist
+//  4. values derived from settings (JVM options)
+type envBuilder struct {
+	keys   []string
+	values map[string]string
+}
+
+func newEnvBuilder(base []string) *envBuilder {
+	b := &envBuilder{values: make(map[string]string)}
+	for _, kv := range base {
+		if key, value, ok := strings.Cut(kv, "="); ok && key != "" {
+			b.Set(key, value)
+		}
+	}
+	return b
+}
+
+func envKey(key string) string {
+	// Environment names are case-insensitive on Windows
+	if runtime.GOOS == "windows" {
+		return strings.ToUpper(key)
+	}
+	return key
+}
+
+// Set sets or overrides a variable
+func (b *envBuilder) Set(key, value string) {
+	k := envKey(key)
+	if _, exists := b.values[k]; !exists {
+		b.keys = append(b.keys, key)
+	}
+	b.values[k] = value
+}
+
+// SetAll applies KEY=VALUE pairs
+func (b *envBuilder) SetAll(vars []string) {
+	for _, kv := range vars {
+		if key, value, ok := strings.Cut(kv, "="); ok && key != "" {
+			b.Set(key, value)
+		}
+	}
+}
+
+// Get returns the current value of a variable
+func (b *envBuilder) Get(key string) (string, bool) {
+	value, ok := b.values[envKey(key)]
+	return value, ok
+}
+
+// Environ returns the merged environment in KEY=VALUE form
+func (b *envBuilder) Environ() []string {
+	environ := make([]string, 0, len(b.keys))
+	for _, key := range b.keys {
+		environ = append(environ, key+"="+b.values[envKey(key)])
+	}
+	return environ
+}
+
+func isBlockedEnvVar(key string) bool {
+	k := envKey(key)
+	for _, blocked := range blockedEnvVars {
+		if prefix, ok := strings.CutSuffix(blocked, "*"); ok {
+			if strings.HasPrefix(k, envKey(prefix)) {
+				return true
+			}
+		} else if k == envKey(blocked) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyProfileEnv adds the profile variables and returns notes about what was applied
+func applyProfileEnv(b *envBuilder, vars map[string]string) []string {
+	if len(vars) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var notes []string
+	applied := 0
+	for _, key := range keys {
+		if key == "" || strings.ContainsAny(key, "= ") {
+			notes = append(notes, fmt.Sprintf("Ignored invalid environment variable name %q", key))
+			continue
+		}
+		if isBlockedEnvVar(key) {
+			notes = append(notes, fmt.Sprintf("Ignored environment variable %s (managed by the launcher)", key))
+			continue
+		}
+		b.Set(key, vars[key])
+		applied++
+	}
+
+	if applied > 0 {
+		notes = append(notes, fmt.Sprintf("Profile environment: %d variables", applied))
+	}
+
+	return notes
+}
+
+// profileClientArgs parses the profile's extra client arguments
+func profileClientArgs(extra string) ([]string, error) {
+	args, err := SplitArgs(extra)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extra client arguments: %w", err)
+	}
+
+	for _, arg := range args {
+		flag, _, _ := strings.Cut(arg, "=")
+		for _, managed := range managedClientFlags {
+			if flag == managed {
+				return nil, fmt.Errorf("extra client arguments may not set %s, it is managed by the launcher", managed)
+			}
+		}
+	}
+
+	return args, nil
+}
+
+// ValidateProfile checks the launch-related fields of a profile before it is saved
+func ValidateProfile(profile config.Profile) error {
+	if _, err := SplitArgs(profile.WrapperCommand); err != nil {
+		return fmt.Errorf("invalid wrapper command: %w", err)
+	}
+	if _, err := profileClientArgs(profile.ExtraArgs); err != nil {
+		return err
+	}
+	for key := range profile.Env {
+		if isBlockedEnvVar(key) {
+			return fmt.Errorf("environment variable %s is managed by the launcher", key)
+		}
+	}
+	if _, err := ResolveUUID(profile, profile.Name); err != nil {
+		return err
+	}
+	if err := ValidateRestartPolicy(profile); err != nil {
+		return err
+	}
+	if profile.Sandbox && runtime.GOOS != "linux" {
+		return fmt.Errorf("the sandbox is only supported on Linux")
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package game
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestEnvBuilder(t *testing.T) {
+	tests := []struct {
+		name   string
+		base   []string
+		layers [][]string
+		want   []string
+	}{
+		{
+			name: "base kept in order",
+			base: []string{"PATH=/usr/bin", "LANG=C"},
+			want: []string{"PATH=/usr/bin", "LANG=C"},
+		},
+		{
+			name:   "later layers override in place",
+			base:   []string{"PATH=/usr/bin", "LANG=C"},
+			layers: [][]string{{"LANG=en_US.UTF-8"}, {"SDL_VIDEODRIVER=wayland"}, {"LANG=de_DE.UTF-8"}},
+			want:   []string{"PATH=/usr/bin", "LANG=de_DE.UTF-8", "SDL_VIDEODRIVER=wayland"},
+		},
+		{
+			name: "malformed entries skipped",
+			base: []string{"NOVALUE", "=hidden", "EMPTY=", "A=b=c"},
+			want: []string{"EMPTY=", "A=b=c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newEnvBuilder(tt.base)
+			for _, layer := range tt.layers {
+				b.SetAll(layer)
+			}
+			if got := b.Environ(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Environ() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvBuilderCase(t *testing.T) {
+	b := newEnvBuilder([]string{"Path=C:\\Windows"})
+	b.Set("PATH", "C:\\Games")
+
+	want := []string{"Path=C:\\Windows", "PATH=C:\\Games"}
+	if runtime.GOOS == "windows" {
+		// One variable, spelled as it first appeared
+		want = []string{"Path=C:\\Games"}
+	}
+	if got := b.Environ(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Environ() = %q, want %q", got, want)
+	}
+}
+
+func TestApplyProfileEnv(t *testing.T) {
+	b := newEnvBuilder([]string{"HOME=/home/user"})
+	notes := applyProfileEnv(b, map[string]string{
+		"MANGOHUD":            "1",
+		"HOME":                "/tmp",
+		"HYLAUNCHER_PROFILE":  "x",
+		"JDK_JAVA_OPTIONS":    "-Xmx1G",
+		"BAD NAME":            "1",
+		"__GL_THREADED_OPTIM": "1",
+	})
+
+	if v, _ := b.Get("HOME"); v != "/home/user" {
+		t.Errorf("HOME = %q, the blocklist was not applied", v)
+	}
+	for _, key := range []string{"HYLAUNCHER_PROFILE", "JDK_JAVA_OPTIONS", "BAD NAME"} {
+		if _, ok := b.Get(key); ok {
+			t.Errorf("%s was set", key)
+		}
+	}
+	for _, key := range []string{"MANGOHUD", "__GL_THREADED_OPTIM"} {
+		if v, _ := b.Get(key); v != "1" {
+			t.Errorf("%s = %q, want 1", key, v)
+		}
+	}
+
+	want := []string{
+		`Ignored invalid environment variable name "BAD NAME"`,
+		"Ignored environment variable HOME (managed by the launcher)",
+		"Ignored environment variable HYLAUNCHER_PROFILE (managed by the launcher)",
+		"Ignored environment variable JDK_JAVA_OPTIONS (managed by the launcher)",
+		"Profile environment: 2 variables",
+	}
+	if !reflect.DeepEqual(notes, want) {
+		t.Errorf("notes = %q, want %q", notes, want)
+	}
+}
+
+func TestProfileClientArgs(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "", want: nil},
+		{in: "--debug --log-level trace", want: []string{"--debug", "--log-level", "trace"}},
+		{in: "--uuid 1234", wantErr: true},
+		{in: "--user-dir=/tmp", wantErr: true},
+		{in: `"--name"`, wantErr: true},
+		{in: "--names x", want: []string{"--names", "x"}},
+		{in: `"unterminated`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := profileClientArgs(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("profileClientArgs(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("profileClientArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
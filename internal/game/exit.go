@@ -0,0 +1,29 @@
+package game
+
+import (
+	"os"
+	"syscall"
+)
+
+// ExitInfo describes how a game process ended
+type ExitInfo struct {
+	ExitCode int    `json:"exitCode"`
+	Signal   string `json:"signal,omitempty"`
+	Crashed  bool   `json:"crashed"`
+}
+
+// GetExitInfo reads the exit status of a finished process
+func GetExitInfo(state *os.ProcessState) ExitInfo {
+	if state == nil {
+		return ExitInfo{ExitCode: -1, Crashed: true}
+	}
+
+	info := ExitInfo{ExitCode: state.ExitCode()}
+
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		info.Signal = status.Signal().String()
+	}
+
+	info.Crashed = info.ExitCode != 0 || info.Signal != ""
+	return info
+}
@@ -0,0 +1,107 @@
+package game
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"HyLauncher/internal/config"
+)
+
+// Display backends
+const (
+	BackendAuto    = "auto"
+	BackendX11     = "x11"
+	BackendWayland = "wayland"
+)
+
+// Hybrid GPU offload modes
+const (
+	OffloadNone   = "none"
+	OffloadMesa   = "mesa"
+	OffloadNvidia = "nvidia"
+)
+
+// GraphicsEnv returns the variables the graphics settings add to the client
+// environment, along with notes about what was applied
+func GraphicsEnv(g config.GraphicsSettings) ([]string, []string) {
+	var vars, notes []string
+
+	if runtime.GOOS == "linux" {
+		switch g.DisplayBackend {
+		case BackendX11, BackendWayland:
+			vars = append(vars, "SDL_VIDEODRIVER="+g.DisplayBackend)
+			notes = append(notes, "Display backend: "+g.DisplayBackend)
+		case "", BackendAuto:
+			// Respect a driver the user exported, otherwise prefer native Wayland
+			if os.Getenv("SDL_VIDEODRIVER") == "" && isWayland() {
+				vars = append(vars, "SDL_VIDEODRIVER=wayland")
+				notes = append(notes, "Display backend: wayland (detected)")
+			}
+		}
+	}
+
+	switch g.GPUOffload {
+	case OffloadMesa:
+		prime := g.DRIPrime
+		if prime == "" {
+			prime = "1"
+		}
+		vars = append(vars, "DRI_PRIME="+prime)
+		notes = append(notes, "GPU offload: DRI_PRIME="+prime)
+	case OffloadNvidia:
+		vars = append(vars,
+			"__NV_PRIME_RENDER_OFFLOAD=1",
+			"__GLX_VENDOR_LIBRARY_NAME=nvidia",
+			"__VK_LAYER_NV_optimus=NVIDIA_only",
+		)
+		notes = append(notes, "GPU offload: NVIDIA PRIME render offload")
+	}
+
+	hints := make([]string, 0, len(g.SDLHints))
+	for name := range g.SDLHints {
+		hints = append(hints, name)
+	}
+	sort.Strings(hints)
+	for _, name := range hints {
+		vars = append(vars, sdlHintVar(name)+"="+g.SDLHints[name])
+	}
+	if len(hints) > 0 {
+		notes = append(notes, fmt.Sprintf("SDL hints: %d", len(hints)))
+	}
+
+	return vars, notes
+}
+
+// ValidateGraphicsSettings checks the backend, offload mode and hint names
+func ValidateGraphicsSettings(g config.GraphicsSettings) error {
+	switch g.DisplayBackend {
+	case "", BackendAuto, BackendX11, BackendWayland:
+	default:
+		return fmt.Errorf("unknown display backend %q", g.DisplayBackend)
+	}
+
+	switch g.GPUOffload {
+	case "", OffloadNone, OffloadMesa, OffloadNvidia:
+	default:
+		return fmt.Errorf("unknown GPU offload mode %q", g.GPUOffload)
+	}
+
+	for name := range g.SDLHints {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			return fmt.Errorf("invalid SDL hint name %q", name)
+		}
+	}
+	return nil
+}
+
+// sdlHintVar turns a hint name into its environment variable, SDL reads hints from SDL_<NAME>
+func sdlHintVar(name string) string {
+	name = strings.ToUpper(name)
+	if strings.HasPrefix(name, "SDL_") {
+		return name
+	}
+	return "SDL_" + name
+}
@@ -0,0 +1,136 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"HyLauncher/internal/platform"
+)
+
+// Pre-launch hooks block the launch, so they get a time limit
+const preLaunchHookTimeout = 2 * time.Minute
+
+// HookContext is passed to hook commands through HYLAUNCHER_* environment variables
+type HookContext struct {
+	ProfileID   string
+	ProfileName string
+	Channel     string
+	Version     string
+	GameDir     string
+	UserDataDir string
+	ExitCode    *int // only set for post-exit hooks
+}
+
+// HookError is returned when a hook command fails
+type HookError struct {
+	Hook string
+	Err  error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s hook failed: %v", e.Hook, e.Err)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// NewHookContext builds the hook context for a launch
+func NewHookContext(opts LaunchOptions, ls *LaunchSettings) HookContext {
+	return HookContext{
+		ProfileID:   opts.Profile.ID,
+		ProfileName: opts.Profile.Name,
+		Channel:     opts.Channel,
+		Version:     opts.Version,
+		GameDir:     ls.GameDir,
+		UserDataDir: ls.UserDataDir,
+	}
+}
+
+// Env returns the hook context as environment variables
+func (h HookContext) Env() []string {
+	vars := []string{
+		"HYLAUNCHER_PROFILE_ID=" + h.ProfileID,
+		"HYLAUNCHER_PROFILE_NAME=" + h.ProfileName,
+		"HYLAUNCHER_CHANNEL=" + h.Channel,
+		"HYLAUNCHER_VERSION=" + h.Version,
+		"HYLAUNCHER_GAME_DIR=" + h.GameDir,
+		"HYLAUNCHER_USER_DIR=" + h.UserDataDir,
+	}
+	if h.ExitCode != nil {
+		vars = append(vars, "HYLAUNCHER_EXIT_CODE="+strconv.Itoa(*h.ExitCode))
+	}
+	return vars
+}
+
+// RunHook runs a hook command line through the system shell and waits for it
+func RunHook(ctx context.Context, name string, command string, hc HookContext, output io.Writer) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	cmd.Dir = hc.GameDir
+	cmd.Env = append(os.Environ(), hc.Env()...)
+	if output != nil {
+		cmd.Stdout = output
+		cmd.Stderr = output
+	}
+	platform.HideConsoleWindow(cmd)
+
+	fmt.Printf("Running %s hook: %s\n", name, command)
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("timed out: %w", ctx.Err())
+		}
+		return &HookError{Hook: name, Err: err}
+	}
+
+	return nil
+}
+
+// runPreLaunchHook runs the profile's pre-launch hook, if any
+func runPreLaunchHook(opts LaunchOptions, ls *LaunchSettings) error {
+	if opts.Profile.PreLaunchHook == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), preLaunchHookTimeout)
+	defer cancel()
+
+	var output io.Writer
+	if opts.Log != nil {
+		output = opts.Log.Writer("pre-launch")
+	}
+
+	return RunHook(ctx, "pre-launch", opts.Profile.PreLaunchHook, NewHookContext(opts, ls), output)
+}
+
+// wrapCommand prefixes the client command line with the profile's wrapper command
+func wrapCommand(wrapper string, exe string, args []string) (string, []string, error) {
+	wrapperArgs, err := SplitArgs(wrapper)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid wrapper command: %w", err)
+	}
+	if len(wrapperArgs) == 0 {
+		return exe, args, nil
+	}
+
+	wrapperExe, err := exec.LookPath(wrapperArgs[0])
+	if err != nil {
+		return "", nil, fmt.Errorf("wrapper command %q not found: %w", wrapperArgs[0], err)
+	}
+
+	wrapped := append(wrapperArgs[1:], exe)
+	wrapped = append(wrapped, args...)
+	return wrapperExe, wrapped, nil
+}
@@ -6,8 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
-	"strconv"
 	"sync"
+	"time"
 
 	"HyLauncher/internal/env"
 	"HyLauncher/internal/java"
@@ -28,28 +28,30 @@ func EnsureInstalled(ctx context.Context, reporter *progress.Reporter) error {
 // EnsureInstalledWithOptions - New function with additional options
 func EnsureInstalledWithOptions(ctx context.Context, channel string, targetVersion int, enableOnlineFix bool, reporter *progress.Reporter) error {
 	// Prevent multiple simultaneous installations
-	installMutex.Lock()
-	if isInstalling {
-		installMutex.Unlock()
-		return fmt.Errorf("installation already in progress")
+	if err := beginInstall(); err != nil {
+		return err
 	}
-	isInstalling = true
-	installMutex.Unlock()
-
-	defer func() {
-		installMutex.Lock()
-		isInstalling = false
-		installMutex.Unlock()
-	}()
+	defer endInstall()
 
 	// Download JRE
 	if err := java.DownloadJRE(ctx, reporter); err != nil {
 		return fmt.Errorf("failed to download Java Runtime: %w", err)
 	}
 
-	// Install Butler
+	// Install Butler, with the built-in patch applier enabled it is only a fallback
 	if _, err := patch.InstallButler(ctx, reporter); err != nil {
-		return fmt.Errorf("failed to install Butler tool: %w", err)
+		if !patch.NativePatching() {
+			return fmt.Errorf("failed to install Butler tool: %w", err)
+		}
+		fmt.Printf("Warning: butler is unavailable, patches are applied without a fallback: %v\n", err)
+	}
+
+	// Pinned builds never change, an installed one needs no update check
+	if targetVersion > 0 && InstalledVersionOf(channel, VersionDir(targetVersion)) == targetVersion {
+		if reporter != nil {
+			reporter.Report(progress.StageComplete, 100, fmt.Sprintf("Version %d is installed", targetVersion))
+		}
+		return nil
 	}
 
 	// Find latest version with details
@@ -98,7 +100,7 @@ func EnsureInstalledWithOptions(ctx context.Context, channel string, targetVersi
 
 	// If targetVersion is 0, use the latest version, otherwise use the specified one
 	installVersion := result.LatestVersion
-	installDirName := "latest"
+	installDirName := LatestDir
 
 	if targetVersion > 0 {
 		// Verify if the requested version exists
@@ -106,7 +108,7 @@ func EnsureInstalledWithOptions(ctx context.Context, channel string, targetVersi
 			return fmt.Errorf("requested version %d is not available: %w", targetVersion, err)
 		}
 		installVersion = targetVersion
-		installDirName = strconv.Itoa(targetVersion)
+		installDirName = VersionDir(targetVersion)
 	}
 
 	if reporter != nil {
@@ -123,10 +125,10 @@ func EnsureInstalledWithOptions(ctx context.Context, channel string, targetVersi
 }
 
 func InstallGame(ctx context.Context, versionType string, remoteVer int, installDirName string, enableOnlineFix bool, reporter *progress.Reporter) error {
-	localStr := patch.GetLocalVersion(versionType)
-	local, _ := strconv.Atoi(localStr)
+	// Every install directory is patched from the build it holds
+	local := InstalledVersionOf(versionType, installDirName)
 
-	gameInstallDir := filepath.Join(env.GetDefaultAppDir(), versionType, "package", "game", installDirName)
+	gameInstallDir := env.GetGameDir(versionType, installDirName)
 
 	// Adjust game client executable to operating system
 	gameClient := "HytaleClient"
@@ -156,26 +158,17 @@ func InstallGame(ctx context.Context, versionType string, remoteVer int, install
 		}
 	}
 
-	// Download the patch file
-	pwrPath, err := patch.DownloadPWR(ctx, versionType, prevVer, remoteVer, reporter)
+	// Pick the cheapest route the server has
+	plan, err := patch.PlanUpdate(ctx, versionType, prevVer, remoteVer)
 	if err != nil {
-		return fmt.Errorf("failed to download game patch: %w", err)
+		return fmt.Errorf("failed to plan game update: %w", err)
 	}
 
-	// Verify the patch file exists and is readable
-	info, err := os.Stat(pwrPath)
-	if err != nil {
-		return fmt.Errorf("patch file not accessible: %w", err)
-	}
-	fmt.Printf("Patch file size: %d bytes\n", info.Size())
-
-	// Apply the patch
-	if reporter != nil {
-		reporter.Report(progress.StagePatch, 0, "Applying game patch...")
+	if err := applyUpdatePlan(ctx, versionType, plan, installDirName, reporter); err != nil {
+		return err
 	}
-
-	if err := patch.ApplyPWRWithOptions(ctx, versionType, pwrPath, installDirName, reporter); err != nil {
-		return fmt.Errorf("failed to apply game patch: %w", err)
+	if err := patch.TrimCache(); err != nil {
+		fmt.Printf("Warning: failed to trim patch cache: %v\n", err)
 	}
 
 	// Verify installation
@@ -183,11 +176,6 @@ func InstallGame(ctx context.Context, versionType string, remoteVer int, install
 		return fmt.Errorf("game installation incomplete: client executable not found at %s", clientPath)
 	}
 
-	// Save the new version
-	if err := patch.SaveLocalVersion(versionType, remoteVer); err != nil {
-		fmt.Printf("Warning: failed to save version info: %v\n", err)
-	}
-
 	// Apply online fix only on windows if enabled
 	if runtime.GOOS == "windows" && enableOnlineFix {
 		if reporter != nil {
@@ -203,6 +191,11 @@ func InstallGame(ctx context.Context, versionType string, remoteVer int, install
 		}
 	}
 
+	// Record what was installed so Verify & Repair can check it later
+	if err := RecordManifest(ctx, versionType, installDirName, remoteVer, usesOnlineFix(enableOnlineFix), reporter); err != nil {
+		fmt.Printf("Warning: failed to record manifest: %v\n", err)
+	}
+
 	if reporter != nil {
 		reporter.Report(progress.StageComplete, 100, "Game installed successfully")
 	}
@@ -210,6 +203,178 @@ func InstallGame(ctx context.Context, versionType string, remoteVer int, install
 	return nil
 }
 
+// applyUpdatePlan downloads and applies every step of the plan in order.
+// Progress covers the whole plan, downloads weighted by size. The version is
+// recorded after each step so an interrupted chain resumes where it stopped.
+func applyUpdatePlan(ctx context.Context, channel string, plan *patch.UpdatePlan, installDirName string, reporter *progress.Reporter) error {
+	steps := len(plan.Steps)
+	var downloaded int64
+
+	for i, step := range plan.Steps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		label := fmt.Sprintf("version %d", step.To)
+		if steps > 1 {
+			label = fmt.Sprintf("patch %d/%d (%d to %d)", i+1, steps, step.From, step.To)
+		}
+
+		start, end := float64(i)*100/float64(steps), float64(i+1)*100/float64(steps)
+		if plan.TotalSize > 0 {
+			start = float64(downloaded) * 100 / float64(plan.TotalSize)
+			end = float64(downloaded+step.Size) * 100 / float64(plan.TotalSize)
+		}
+		downloaded += step.Size
+
+		if reporter != nil {
+			reporter.Report(progress.StagePWR, start, "Downloading "+label+"...")
+		}
+		pwrPath, err := patch.DownloadPatch(ctx, channel, step, progress.NewScaler(reporter, progress.StagePWR, start, end))
+		if err != nil {
+			return fmt.Errorf("failed to download game patch: %w", err)
+		}
+
+		// Verify the patch file exists and is readable
+		info, err := os.Stat(pwrPath)
+		if err != nil {
+			return fmt.Errorf("patch file not accessible: %w", err)
+		}
+		fmt.Printf("Patch file size: %d bytes\n", info.Size())
+
+		if reporter != nil {
+			reporter.Report(progress.StagePatch, float64(i)*100/float64(steps), "Applying "+label+"...")
+		}
+		scaler := progress.NewScaler(reporter, progress.StagePatch, float64(i)*100/float64(steps), float64(i+1)*100/float64(steps))
+		if step.From == 0 {
+			if err := applyFullBuild(ctx, channel, pwrPath, installDirName, scaler); err != nil {
+				return err
+			}
+		} else if err := patch.ApplyPatch(ctx, channel, pwrPath, installDirName, scaler); err != nil {
+			return fmt.Errorf("failed to apply game patch: %w", err)
+		}
+
+		// Save the new version
+		if err := RecordInstalledVersion(channel, installDirName, step.To); err != nil {
+			fmt.Printf("Warning: failed to save version info: %v\n", err)
+		}
+		if installDirName == LatestDir {
+			if err := patch.SaveLocalVersion(channel, step.To); err != nil {
+				fmt.Printf("Warning: failed to save version info: %v\n", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Prefixes of the directories a full build passes through next to the install
+const (
+	incomingPrefix = ".incoming-"
+	oldPrefix      = ".old-"
+)
+
+// applyFullBuild applies a full build into an empty staging directory and swaps
+// it in for the install only once it succeeded, so a failed download or apply
+// leaves the previous build playable
+func applyFullBuild(ctx context.Context, channel string, pwrPath string, installDirName string, scaler *progress.Scaler) error {
+	installDir := InstallDir(channel, installDirName)
+	incomingDir := InstallDir(channel, incomingPrefix+installDirName)
+	oldDir := InstallDir(channel, oldPrefix+installDirName)
+
+	_ = os.RemoveAll(incomingDir)
+	if err := patch.ApplyPatchTo(ctx, channel, pwrPath, incomingDir, scaler); err != nil {
+		_ = os.RemoveAll(incomingDir)
+		return fmt.Errorf("failed to apply game patch: %w", err)
+	}
+
+	if err := swapDir(incomingDir, installDir, oldDir); err != nil {
+		_ = os.RemoveAll(incomingDir)
+		return fmt.Errorf("failed to replace game directory: %w", err)
+	}
+	return nil
+}
+
+// swapDir replaces dst with src. The old dst is moved aside first and put back
+// when src cannot take its place.
+func swapDir(src, dst, old string) error {
+	_ = os.RemoveAll(old)
+
+	hadOld := true
+	if err := renameRetry(dst, old); err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		hadOld = false
+	}
+
+	if err := renameRetry(src, dst); err != nil {
+		if hadOld {
+			if restoreErr := os.Rename(old, dst); restoreErr != nil {
+				fmt.Printf("Warning: failed to restore %s: %v\n", dst, restoreErr)
+			}
+		}
+		return err
+	}
+
+	if hadOld {
+		if err := os.RemoveAll(old); err != nil {
+			fmt.Printf("Warning: failed to remove previous build: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// renameRetry retries a rename a few times, antivirus may still hold files on Windows
+func renameRetry(src, dst string) error {
+	var err error
+	for i := 0; i < 5; i++ {
+		if err = os.Rename(src, dst); err == nil || os.IsNotExist(err) {
+			return err
+		}
+		time.Sleep(time.Second)
+	}
+	return err
+}
+
+// ReinstallButler installs butler again after its settings changed
+func ReinstallButler(ctx context.Context, reporter *progress.Reporter) (string, error) {
+	if err := beginInstall(); err != nil {
+		return "", err
+	}
+	defer endInstall()
+
+	return patch.InstallButler(ctx, reporter)
+}
+
+// ClearPatchCache empties the patch cache unless an install is using it
+func ClearPatchCache() error {
+	if err := beginInstall(); err != nil {
+		return err
+	}
+	defer endInstall()
+
+	return patch.ClearCache()
+}
+
+// beginInstall takes the install lock shared by installs and relocations
+func beginInstall() error {
+	installMutex.Lock()
+	defer installMutex.Unlock()
+
+	if isInstalling {
+		return fmt.Errorf("installation already in progress")
+	}
+	isInstalling = true
+	return nil
+}
+
+func endInstall() {
+	installMutex.Lock()
+	isInstalling = false
+	installMutex.Unlock()
+}
+
 func getFirstURL(urls []string) string {
 	if len(urls) == 0 {
 		return "none"
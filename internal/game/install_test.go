@@ -0,0 +1,57 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSwapDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing bool // dst holds a previous build
+		staged   bool // src was applied
+		want     string
+		wantErr  bool
+	}{
+		{name: "replaces the previous build", existing: true, staged: true, want: "new"},
+		{name: "first install", staged: true, want: "new"},
+		{name: "failed swap keeps the previous build", existing: true, want: "old", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			src := filepath.Join(root, incomingPrefix+"latest")
+			dst := filepath.Join(root, "latest")
+			old := filepath.Join(root, oldPrefix+"latest")
+
+			if tt.existing {
+				writeTestFile(t, filepath.Join(dst, "Client", "build"), "old")
+				writeTestFile(t, filepath.Join(dst, "Client", "removed"), "old")
+			}
+			if tt.staged {
+				writeTestFile(t, filepath.Join(src, "Client", "build"), "new")
+			}
+
+			err := swapDir(src, dst, old)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("swapDir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dst, "Client", "build"))
+			if err != nil || string(data) != tt.want {
+				t.Errorf("build = %q (%v), want %q", data, err, tt.want)
+			}
+			_, err = os.Stat(filepath.Join(dst, "Client", "removed"))
+			if kept := err == nil; kept != (tt.want == "old") {
+				t.Errorf("file of the previous build kept = %v", kept)
+			}
+			for _, dir := range []string{src, old} {
+				if _, err := os.Stat(dir); !os.IsNotExist(err) {
+					t.Errorf("%s left behind", filepath.Base(dir))
+				}
+			}
+		})
+	}
+}
@@ -7,22 +7,108 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 
+	"HyLauncher/internal/config"
 	"HyLauncher/internal/env"
 	"HyLauncher/internal/java"
+	"HyLauncher/internal/platform"
 )
 
-func Launch(playerName string, channel string, playerUUID string, version string, enableOnlineFix bool) (*exec.Cmd, error) {
-	baseDir := env.GetDefaultAppDir()
-	gameDir := filepath.Join(baseDir, channel, "package", "game", version)
-	userDataDir := filepath.Join(baseDir, "UserData")
+// LaunchOptions describes a single client launch
+type LaunchOptions struct {
+	PlayerName string
+	PlayerUUID string
+	Channel    string
+	Version    string
+	OnlineFix  bool
+	Settings   config.GameSettings
+	Graphics   config.GraphicsSettings
+	Profile    config.Profile
+	Log        *SessionLog // receives client output, falls back to the launcher's stdout
+	Detached   bool        // the client outlives the launcher, output goes straight to the log file
+}
+
+// NewLaunchOptions resolves the launch options of a profile from the config
+func NewLaunchOptions(cfg *config.Config, profile config.Profile, playerName string) (LaunchOptions, error) {
+	playerUUID, err := ResolveUUID(profile, playerName)
+	if err != nil {
+		return LaunchOptions{}, err
+	}
 
-	if enableOnlineFix {
+	channel := cfg.Settings.Channel
+	if channel == "" {
+		channel = "release"
+	}
+
+	return LaunchOptions{
+		PlayerName: playerName,
+		PlayerUUID: playerUUID,
+		Channel:    channel,
+		Version:    VersionDir(cfg.Settings.GameVersion),
+		OnlineFix:  cfg.Settings.OnlineFix,
+		Settings:   cfg.Settings,
+		Graphics:   cfg.Graphics,
+		Profile:    profile,
+	}, nil
+}
+
+// InstallDir returns the directory of an installed game build
+func InstallDir(channel string, version string) string {
+	return env.GetGameDir(channel, version)
+}
+
+// Launch prepares the installation and UserData, runs the pre-launch hook and
+// starts the client
+func Launch(opts LaunchOptions) (*exec.Cmd, *LaunchSettings, error) {
+	if opts.OnlineFix {
+		gameDir := InstallDir(opts.Channel, opts.Version)
 		if err := EnsureServerAndClientFix(context.Background(), gameDir, nil); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
+	cmd, launchSettings, err := buildLaunch(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_ = os.MkdirAll(launchSettings.UserDataDir, 0755)
+
+	skipped, err := applyClientSettings(launchSettings.UserDataDir, opts.Settings)
+	if err != nil {
+		fmt.Printf("Warning: failed to apply display settings: %v\n", err)
+		launchSettings.Applied = append(launchSettings.Applied, "Display settings not applied: "+err.Error())
+	} else if len(skipped) > 0 {
+		// The client writes its settings file on the first run
+		launchSettings.Applied = append(launchSettings.Applied, "Not yet defined by the client: "+strings.Join(skipped, ", "))
+	}
+
+	if err := runPreLaunchHook(opts, launchSettings); err != nil {
+		return nil, nil, err
+	}
+
+	fmt.Printf(
+		"Launching %s (%s - %s) with UUID %s\n",
+		opts.PlayerName,
+		opts.Channel,
+		opts.Version,
+		opts.PlayerUUID,
+	)
+	for _, applied := range launchSettings.Applied {
+		fmt.Printf("  %s\n", applied)
+	}
+
+	return cmd, launchSettings, cmd.Start()
+}
+
+// buildLaunch resolves the command, arguments and environment of a launch. It
+// only reads the disk: the online fix, UserData and display settings are left
+// to Launch, so a preview changes nothing.
+func buildLaunch(opts LaunchOptions) (*exec.Cmd, *LaunchSettings, error) {
+	gameDir := InstallDir(opts.Channel, opts.Version)
+	userDataDir := UserDataDir(opts.Profile)
+
 	gameClient := "HytaleClient"
 	if runtime.GOOS == "windows" {
 		gameClient += ".exe"
@@ -31,37 +117,90 @@ func Launch(playerName string, channel string, playerUUID string, version string
 	clientPath := filepath.Join(gameDir, "Client", gameClient)
 	// Check if client executable exists
 	if _, err := os.Stat(clientPath); err != nil {
-		return nil, fmt.Errorf("game executable not found at %s: %w", clientPath, err)
+		return nil, nil, fmt.Errorf("game executable not found at %s: %w", clientPath, err)
 	}
 
 	javaBin, err := java.GetJavaExec()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	launchSettings, err := BuildLaunchSettings(opts.Settings)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	_ = os.MkdirAll(userDataDir, 0755)
+	launchSettings.GameDir = gameDir
+	launchSettings.UserDataDir = userDataDir
 
-	cmd := exec.Command(clientPath,
+	clientArgs := []string{
 		"--app-dir", gameDir,
 		"--user-dir", userDataDir,
 		"--java-exec", javaBin,
 		"--auth-mode", "offline",
-		"--uuid", playerUUID,
-		"--name", playerName,
-	)
+		"--uuid", opts.PlayerUUID,
+		"--name", opts.PlayerName,
+	}
 
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	extraArgs, err := profileClientArgs(opts.Profile.ExtraArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(extraArgs) > 0 {
+		clientArgs = append(clientArgs, extraArgs...)
+		launchSettings.Applied = append(launchSettings.Applied, "Extra client arguments: "+JoinArgs(extraArgs))
+	}
 
-	setSDLVideoDriver(cmd)
+	environ := newEnvBuilder(os.Environ())
+	graphicsEnv, graphicsNotes := GraphicsEnv(opts.Graphics)
+	environ.SetAll(graphicsEnv)
+	launchSettings.Applied = append(launchSettings.Applied, graphicsNotes...)
+	launchSettings.Applied = append(launchSettings.Applied, applyProfileEnv(environ, opts.Profile.Env)...)
+	environ.SetAll(launchSettings.Env)
 
-	fmt.Printf(
-		"Launching %s (%s - %s) with UUID %s\n",
-		playerName,
-		channel,
-		version,
-		playerUUID,
-	)
+	exe, args, err := wrapCommand(opts.Profile.WrapperCommand, clientPath, clientArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if exe != clientPath {
+		launchSettings.Applied = append(launchSettings.Applied, "Wrapper: "+opts.Profile.WrapperCommand)
+	}
+
+	if opts.Profile.Sandbox {
+		mounts := sandboxMounts{
+			GameDir:     gameDir,
+			UserDataDir: userDataDir,
+			JREDir:      filepath.Dir(filepath.Dir(javaBin)),
+		}
+		exe, args, err = sandboxCommand(exe, args, mounts, opts.Profile.SandboxNetwork, environ)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		network := "off"
+		if opts.Profile.SandboxNetwork {
+			network = "on"
+		}
+		launchSettings.Applied = append(launchSettings.Applied, "Sandbox: bubblewrap, network "+network)
+	}
+
+	cmd := exec.Command(exe, args...)
+
+	if opts.Log != nil && opts.Detached && opts.Log.File() != nil {
+		// A pipe would break once the launcher exits, hand the file itself to the client
+		cmd.Stdout = opts.Log.File()
+		cmd.Stderr = opts.Log.File()
+	} else if opts.Log != nil {
+		cmd.Stdout = opts.Log.Writer("stdout")
+		cmd.Stderr = opts.Log.Writer("stderr")
+	} else {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+	cmd.Env = environ.Environ()
+
+	// Own process group so stopping the game also reaches the JVM it spawns
+	platform.SetProcessGroup(cmd)
 
-	return cmd, cmd.Start()
+	return cmd, launchSettings, nil
 }
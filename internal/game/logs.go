@@ -0,0 +1,308 @@
+package game
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"HyLauncher/internal/env"
+)
+
+const (
+	maxGameLogSize  = 10 * 1024 * 1024 // rotate a session log once it grows past this
+	maxGameLogCount = 20               // number of sessions kept on disk
+	maxTailLines    = 100              // lines kept in memory for crash reports
+	gameLogExt      = ".log"
+	rotatedLogExt   = ".log.1"
+)
+
+// LogLine is a single line of game output
+type LogLine struct {
+	SessionID string    `json:"sessionId"`
+	Stream    string    `json:"stream"`
+	Line      string    `json:"line"`
+	Time      time.Time `json:"time"`
+}
+
+// GameLogInfo describes a stored session log
+type GameLogInfo struct {
+	SessionID string    `json:"sessionId"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"modTime"`
+}
+
+// SessionLog writes the output of one game session to logs/game/<id>.log
+// and forwards every complete line to a callback
+type SessionLog struct {
+	ID string
+
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	size    int64
+	tail    []string
+	writers []*lineWriter
+	onLine  func(LogLine)
+}
+
+func GameLogDir() string {
+	return filepath.Join(env.GetDefaultAppDir(), "logs", "game")
+}
+
+// NewSessionLog creates a log file for a new game session and prunes old ones
+func NewSessionLog(onLine func(LogLine)) (*SessionLog, error) {
+	return NewSessionLogIn(GameLogDir(), onLine)
+}
+
+// NewSessionLogIn creates a session log in dir, keeping the same rotation and retention
+func NewSessionLogIn(dir string, onLine func(LogLine)) (*SessionLog, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	pruneLogs(dir, maxGameLogCount-1)
+
+	base := time.Now().Format("2006-01-02_15-04-05")
+	id := base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(dir, id+gameLogExt)); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	path := filepath.Join(dir, id+gameLogExt)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session log: %w", err)
+	}
+
+	return &SessionLog{
+		ID:     id,
+		path:   path,
+		file:   f,
+		onLine: onLine,
+	}, nil
+}
+
+// Writer returns a writer for one output stream (stdout or stderr)
+func (l *SessionLog) Writer(stream string) io.Writer {
+	w := &lineWriter{log: l, stream: stream}
+
+	l.mu.Lock()
+	l.writers = append(l.writers, w)
+	l.mu.Unlock()
+
+	return w
+}
+
+// Path returns the current log file path
+func (l *SessionLog) Path() string {
+	return l.path
+}
+
+// File returns the log file for a process that must outlive the launcher. Output
+// written to it directly is not rotated, kept in the tail or forwarded.
+func (l *SessionLog) File() *os.File {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file
+}
+
+// Tail returns the last lines written to the log
+func (l *SessionLog) Tail() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tail := make([]string, len(l.tail))
+	copy(tail, l.tail)
+	return tail
+}
+
+// Flush writes out the unterminated last line of every stream. A crash often
+// leaves its last line without a newline.
+func (l *SessionLog) Flush() {
+	l.mu.Lock()
+	writers := make([]*lineWriter, len(l.writers))
+	copy(writers, l.writers)
+	l.mu.Unlock()
+
+	for _, w := range writers {
+		w.flush()
+	}
+}
+
+// Close flushes and closes the log file
+func (l *SessionLog) Close() error {
+	l.Flush()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+func (l *SessionLog) writeLine(stream, line string) {
+	l.mu.Lock()
+	l.tail = append(l.tail, fmt.Sprintf("[%s] %s", stream, line))
+	if len(l.tail) > maxTailLines {
+		l.tail = l.tail[len(l.tail)-maxTailLines:]
+	}
+	if l.file != nil {
+		if l.size >= maxGameLogSize {
+			l.rotate()
+		}
+		if l.file != nil {
+			n, _ := fmt.Fprintf(l.file, "[%s] %s\n", stream, line)
+			l.size += int64(n)
+		}
+	}
+	l.mu.Unlock()
+
+	if l.onLine != nil {
+		l.onLine(LogLine{
+			SessionID: l.ID,
+			Stream:    stream,
+			Line:      line,
+			Time:      time.Now(),
+		})
+	}
+}
+
+// rotate moves the current file to <id>.log.1 and starts a fresh one, must hold mu
+func (l *SessionLog) rotate() {
+	_ = l.file.Close()
+	rotated := strings.TrimSuffix(l.path, gameLogExt) + rotatedLogExt
+	_ = os.Remove(rotated)
+	_ = os.Rename(l.path, rotated)
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		fmt.Printf("Warning: failed to rotate game log: %v\n", err)
+		l.file = nil
+		return
+	}
+	l.file = f
+	l.size = 0
+}
+
+// lineWriter splits a stream into lines before handing them to the session log
+type lineWriter struct {
+	log    *SessionLog
+	stream string
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+	for {
+		idx := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if idx < 0 {
+			break
+		}
+		line := strings.TrimRight(string(w.buf.Next(idx+1)), "\r\n")
+		w.log.writeLine(w.stream, line)
+	}
+	return len(p), nil
+}
+
+// flush hands over a last line that never got its newline
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() == 0 {
+		return
+	}
+	line := strings.TrimRight(w.buf.String(), "\r\n")
+	w.buf.Reset()
+	w.log.writeLine(w.stream, line)
+}
+
+// ListGameLogs returns stored game session logs, newest first
+func ListGameLogs() ([]GameLogInfo, error) {
+	return listLogs(GameLogDir())
+}
+
+func listLogs(dir string) ([]GameLogInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []GameLogInfo{}, nil
+		}
+		return nil, err
+	}
+
+	logs := []GameLogInfo{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), gameLogExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		logs = append(logs, GameLogInfo{
+			SessionID: strings.TrimSuffix(entry.Name(), gameLogExt),
+			Size:      info.Size(),
+			ModTime:   info.ModTime(),
+		})
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].SessionID > logs[j].SessionID
+	})
+
+	return logs, nil
+}
+
+// ReadGameLog returns the full log of a game session, including its rotated part
+func ReadGameLog(sessionID string) (string, error) {
+	return readLog(GameLogDir(), sessionID)
+}
+
+func readLog(dir string, sessionID string) (string, error) {
+	if sessionID == "" || sessionID != filepath.Base(sessionID) || strings.Contains(sessionID, "..") {
+		return "", fmt.Errorf("invalid session id: %q", sessionID)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, sessionID+gameLogExt))
+	if err != nil {
+		return "", err
+	}
+
+	if rotated, err := os.ReadFile(filepath.Join(dir, sessionID+rotatedLogExt)); err == nil {
+		data = append(rotated, data...)
+	}
+
+	return string(data), nil
+}
+
+// pruneLogs removes the oldest session logs so that at most keep remain
+func pruneLogs(dir string, keep int) {
+	logs, err := listLogs(dir)
+	if err != nil || len(logs) <= keep {
+		return
+	}
+
+	for _, old := range logs[keep:] {
+		_ = os.Remove(filepath.Join(dir, old.SessionID+gameLogExt))
+		_ = os.Remove(filepath.Join(dir, old.SessionID+rotatedLogExt))
+	}
+}
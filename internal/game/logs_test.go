@@ -0,0 +1,71 @@
+package game
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSessionLogLines(t *testing.T) {
+	var forwarded []string
+	l, err := NewSessionLogIn(t.TempDir(), func(line LogLine) {
+		forwarded = append(forwarded, line.Stream+":"+line.Line)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := l.Writer("stdout")
+	stderr := l.Writer("stderr")
+	io.WriteString(stdout, "first\r\nsec")
+	io.WriteString(stderr, "Exception in thread")
+	io.WriteString(stdout, "ond\n")
+
+	want := []string{"stdout:first", "stdout:second"}
+	if !reflect.DeepEqual(forwarded, want) {
+		t.Fatalf("before close forwarded %q, want %q", forwarded, want)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want = append(want, "stderr:Exception in thread")
+	if !reflect.DeepEqual(forwarded, want) {
+		t.Errorf("after close forwarded %q, want %q", forwarded, want)
+	}
+
+	tail := l.Tail()
+	if len(tail) != 3 || tail[2] != "[stderr] Exception in thread" {
+		t.Errorf("tail = %q", tail)
+	}
+
+	data, err := os.ReadFile(l.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(data), "[stderr] Exception in thread\n") {
+		t.Errorf("log file does not end with the partial line:\n%s", data)
+	}
+}
+
+func TestSessionLogFlushKeepsWriting(t *testing.T) {
+	l, err := NewSessionLogIn(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	w := l.Writer("stdout")
+	io.WriteString(w, "partial")
+	l.Flush()
+	l.Flush()
+	io.WriteString(w, "next\n")
+
+	want := []string{"[stdout] partial", "[stdout] next"}
+	if got := l.Tail(); !reflect.DeepEqual(got, want) {
+		t.Errorf("tail = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// LaunchPreview is the exact client command a launch would run
+type LaunchPreview struct {
+	Executable    string          `json:"executable"`
+	Args          []string        `json:"args"`
+	Env           []EnvChange     `json:"env"` // only what differs from the launcher's environment
+	WorkingDir    string          `json:"workingDir"`
+	Command       string          `json:"command"` // copy-pasteable shell command
+	PreLaunchHook string          `json:"preLaunchHook,omitempty"`
+	Settings      *LaunchSettings `json:"settings"`
+}
+
+// EnvChange is a variable the launch sets or overrides
+type EnvChange struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Previous string `json:"previous,omitempty"`
+}
+
+// PreviewLaunch resolves a launch like Launch does but starts nothing. The
+// pre-launch hook is reported instead of run.
+func PreviewLaunch(opts LaunchOptions) (*LaunchPreview, error) {
+	cmd, launchSettings, err := buildLaunch(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.OnlineFix {
+		launchSettings.Applied = append(launchSettings.Applied, "Online fix: checked and applied at launch")
+	}
+
+	workingDir := cmd.Dir
+	if workingDir == "" {
+		workingDir, _ = os.Getwd()
+	}
+
+	preview := &LaunchPreview{
+		Executable:    cmd.Path,
+		Args:          cmd.Args[1:],
+		Env:           envDiff(os.Environ(), cmd.Env),
+		WorkingDir:    workingDir,
+		PreLaunchHook: opts.Profile.PreLaunchHook,
+		Settings:      launchSettings,
+	}
+	preview.Command = shellCommand(preview)
+
+	return preview, nil
+}
+
+func envDiff(base, environ []string) []EnvChange {
+	before := newEnvBuilder(base)
+
+	var changes []EnvChange
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		previous, ok := before.Get(name)
+		if ok && previous == value {
+			continue
+		}
+		changes = append(changes, EnvChange{Name: name, Value: value, Previous: previous})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// shellCommand renders the preview for sh, or cmd.exe on Windows
+func shellCommand(p *LaunchPreview) string {
+	var parts []string
+
+	if runtime.GOOS == "windows" {
+		parts = append(parts, "cd /d "+cmdQuote(p.WorkingDir))
+		for _, change := range p.Env {
+			parts = append(parts, `set "`+change.Name+"="+change.Value+`"`)
+		}
+		line := cmdQuote(p.Executable)
+		for _, arg := range p.Args {
+			line += " " + cmdQuote(arg)
+		}
+		return strings.Join(append(parts, line), " && ")
+	}
+
+	line := "cd " + shellQuote(p.WorkingDir) + " && env"
+	for _, change := range p.Env {
+		line += " " + shellQuote(change.Name+"="+change.Value)
+	}
+	line += " " + shellQuote(p.Executable)
+	for _, arg := range p.Args {
+		line += " " + shellQuote(arg)
+	}
+	return line
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,+@%", r))
+	}) == -1 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func cmdQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"&|<>^") {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
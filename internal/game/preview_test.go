@@ -0,0 +1,90 @@
+//go:build linux || darwin
+
+package game
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"HyLauncher/internal/config"
+	"HyLauncher/internal/env"
+)
+
+// snapshot lists every path under dir with its size and mode
+func snapshot(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		files[path] = fmt.Sprintf("%v %v %d", info.Mode(), info.ModTime(), info.Size())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestPreviewLaunchChangesNothing(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	env.SetInstallDir("")
+
+	appDir := env.GetDefaultAppDir()
+	gameDir := env.GetGameDir("release", "latest")
+	writeTestFile(t, filepath.Join(gameDir, "Client", "HytaleClient"), "client")
+	javaBin := filepath.Join(env.GetJREDir(), "latest", "bin", "java")
+	writeTestFile(t, javaBin, "#!/bin/sh\nexit 0\n")
+	if err := os.Chmod(javaBin, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	fullscreen := true
+	opts := LaunchOptions{
+		PlayerName: "Steve",
+		PlayerUUID: "0d7f6c52-5a4e-4b0e-9d1e-6f1f2f3a4b5c",
+		Channel:    "release",
+		Version:    "latest",
+		OnlineFix:  true,
+		Settings:   config.GameSettings{MaxMemory: 4, Fullscreen: &fullscreen},
+		Profile:    config.Profile{ID: "p1", IsolatedUserData: true, Env: map[string]string{"MANGOHUD": "1"}},
+	}
+
+	before := snapshot(t, appDir)
+	preview, err := PreviewLaunch(opts)
+	if err != nil {
+		t.Fatalf("PreviewLaunch: %v", err)
+	}
+	after := snapshot(t, appDir)
+
+	if len(before) != len(after) {
+		t.Errorf("preview changed the app directory: %d entries before, %d after", len(before), len(after))
+	}
+	for path, state := range before {
+		if after[path] != state {
+			t.Errorf("preview changed %s", path)
+		}
+	}
+
+	if preview.Executable != filepath.Join(gameDir, "Client", "HytaleClient") {
+		t.Errorf("executable = %s", preview.Executable)
+	}
+	if !strings.Contains(strings.Join(preview.Args, " "), "--user-dir "+IsolatedUserDataDir("p1")) {
+		t.Errorf("args %q do not use the isolated UserData", preview.Args)
+	}
+
+	found := false
+	for _, change := range preview.Env {
+		if change.Name == "MANGOHUD" && change.Value == "1" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("env diff %v is missing MANGOHUD", preview.Env)
+	}
+}
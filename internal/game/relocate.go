@@ -0,0 +1,237 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"HyLauncher/internal/env"
+	"HyLauncher/internal/progress"
+)
+
+// MoveInstallation moves the game installations, the JRE and the cache from the current
+// install root to gameDir and makes it the install root, an empty gameDir meaning the
+// default app directory. Entries are renamed when possible and copied otherwise (e.g. to
+// another filesystem). Sources of copies are only deleted once every entry has moved, and
+// a failure moves the entries already done back, so the installation is never split.
+func MoveInstallation(ctx context.Context, gameDir string, reporter *progress.Reporter) error {
+	if err := beginInstall(); err != nil {
+		return err
+	}
+	defer endInstall()
+
+	from, err := filepath.Abs(env.GetInstallDir())
+	if err != nil {
+		return err
+	}
+	to := gameDir
+	if to == "" {
+		to = env.GetDefaultAppDir()
+	}
+	to, err = filepath.Abs(to)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		env.SetInstallDir(gameDir)
+		return nil
+	}
+
+	var entries []string
+	for _, name := range env.InstallEntries() {
+		src := filepath.Join(from, name)
+		if _, err := os.Lstat(src); os.IsNotExist(err) {
+			continue
+		}
+		if isWithin(src, to) {
+			return fmt.Errorf("cannot move the installation into itself (%s)", to)
+		}
+		if _, err := os.Lstat(filepath.Join(to, name)); err == nil {
+			return fmt.Errorf("target already contains %s", name)
+		}
+		entries = append(entries, name)
+	}
+
+	if err := os.MkdirAll(to, 0755); err != nil {
+		return fmt.Errorf("failed to create target directory: %w", err)
+	}
+
+	var total int64
+	sizes := make(map[string]int64, len(entries))
+	for _, name := range entries {
+		sizes[name] = dirSize(filepath.Join(from, name))
+		total += sizes[name]
+	}
+	mp := &moveProgress{reporter: reporter, total: total}
+	mp.report("Moving installation...", "")
+
+	var moved []movedEntry
+	for _, name := range entries {
+		src := filepath.Join(from, name)
+		dst := filepath.Join(to, name)
+
+		if err := os.Rename(src, dst); err == nil {
+			moved = append(moved, movedEntry{src: src, dst: dst, renamed: true})
+			mp.add(sizes[name], name)
+			continue
+		}
+
+		// Renaming fails across filesystems, fall back to copying
+		fmt.Printf("Rename of %s failed, copying to %s\n", src, dst)
+		if err := copyTree(ctx, src, dst, mp); err != nil {
+			_ = os.RemoveAll(dst)
+			return fmt.Errorf("failed to copy %s: %w", name, rollbackMove(moved, err))
+		}
+		moved = append(moved, movedEntry{src: src, dst: dst})
+	}
+
+	env.SetInstallDir(gameDir)
+
+	for _, entry := range moved {
+		if entry.renamed {
+			continue
+		}
+		if err := os.RemoveAll(entry.src); err != nil {
+			fmt.Printf("Warning: failed to remove %s after copying: %v\n", entry.src, err)
+		}
+	}
+
+	reporter.Report(progress.StageMove, 100, "Installation moved")
+	return nil
+}
+
+// movedEntry is a top level entry that has reached the new install root
+type movedEntry struct {
+	src     string
+	dst     string
+	renamed bool // false when copied, the source is still in place
+}
+
+// rollbackMove returns moved entries to the old install root. Copies are simply
+// dropped, their sources were kept. Entries that cannot go back are added to err.
+func rollbackMove(moved []movedEntry, err error) error {
+	for i := len(moved) - 1; i >= 0; i-- {
+		entry := moved[i]
+		if entry.renamed {
+			if renameErr := os.Rename(entry.dst, entry.src); renameErr != nil {
+				err = fmt.Errorf("%w; %s could not be moved back from %s: %v", err, filepath.Base(entry.src), entry.dst, renameErr)
+			}
+			continue
+		}
+		_ = os.RemoveAll(entry.dst)
+	}
+	return err
+}
+
+// moveProgress turns copied bytes into progress updates
+type moveProgress struct {
+	reporter *progress.Reporter
+	total    int64
+	done     int64
+	lastPct  int
+}
+
+func (m *moveProgress) add(n int64, file string) {
+	m.done += n
+	if pct := m.percent(); pct != m.lastPct {
+		m.lastPct = pct
+		m.report("Moving installation...", file)
+	}
+}
+
+func (m *moveProgress) percent() int {
+	if m.total <= 0 {
+		return 0
+	}
+	return int(m.done * 100 / m.total)
+}
+
+func (m *moveProgress) report(message, file string) {
+	m.reporter.ReportWithFile(progress.StageMove, float64(m.percent()), message, file)
+}
+
+// copyTree copies a directory keeping file modes and symlinks
+func copyTree(ctx context.Context, src, dst string, mp *moveProgress) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		default:
+			return copyFileMode(path, target, info.Mode().Perm(), func(n int64) {
+				mp.add(n, rel)
+			})
+		}
+	})
+}
+
+func copyFileMode(src, dst string, mode os.FileMode, onWrite func(int64)) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(&countingWriter{w: out, onWrite: onWrite}, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+type countingWriter struct {
+	w       io.Writer
+	onWrite func(int64)
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.onWrite(int64(n))
+	return n, err
+}
+
+func dirSize(path string) int64 {
+	var size int64
+	_ = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// isWithin reports whether path is dir or inside it
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"HyLauncher/internal/env"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMoveInstallation(t *testing.T) {
+	from := t.TempDir()
+	to := filepath.Join(t.TempDir(), "games")
+	env.SetInstallDir(from)
+	defer env.SetInstallDir("")
+
+	writeTestFile(t, filepath.Join(from, "release", "package", "game", "1", "Client", "HytaleClient"), "client")
+	writeTestFile(t, filepath.Join(from, "cache", "1.pwr"), "patch")
+
+	if err := MoveInstallation(context.Background(), to, nil); err != nil {
+		t.Fatalf("MoveInstallation: %v", err)
+	}
+
+	if got := env.GetInstallDir(); got != to {
+		t.Errorf("install dir = %s, want %s", got, to)
+	}
+	for _, rel := range []string{"release/package/game/1/Client/HytaleClient", "cache/1.pwr"} {
+		if _, err := os.Stat(filepath.Join(to, rel)); err != nil {
+			t.Errorf("%s not moved: %v", rel, err)
+		}
+		if _, err := os.Stat(filepath.Join(from, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s still in the old root", rel)
+		}
+	}
+}
+
+func TestMoveInstallationRefusesExistingTarget(t *testing.T) {
+	from := t.TempDir()
+	to := t.TempDir()
+	env.SetInstallDir(from)
+	defer env.SetInstallDir("")
+
+	writeTestFile(t, filepath.Join(from, "cache", "1.pwr"), "patch")
+	writeTestFile(t, filepath.Join(to, "cache", "other.pwr"), "other")
+
+	if err := MoveInstallation(context.Background(), to, nil); err == nil {
+		t.Fatal("expected an error for a target that already has a cache")
+	}
+	if got := env.GetInstallDir(); got != from {
+		t.Errorf("install dir changed to %s", got)
+	}
+}
+
+func TestRollbackMove(t *testing.T) {
+	from := t.TempDir()
+	to := t.TempDir()
+
+	// release was renamed, cache was copied and still has its source
+	writeTestFile(t, filepath.Join(to, "release", "game"), "game")
+	writeTestFile(t, filepath.Join(from, "cache", "1.pwr"), "patch")
+	writeTestFile(t, filepath.Join(to, "cache", "1.pwr"), "patch")
+
+	moved := []movedEntry{
+		{src: filepath.Join(from, "release"), dst: filepath.Join(to, "release"), renamed: true},
+		{src: filepath.Join(from, "cache"), dst: filepath.Join(to, "cache")},
+	}
+	cause := errors.New("disk full")
+	if err := rollbackMove(moved, cause); !errors.Is(err, cause) || err.Error() != cause.Error() {
+		t.Errorf("rollbackMove = %v, want %v", err, cause)
+	}
+
+	for _, rel := range []string{"release/game", "cache/1.pwr"} {
+		if _, err := os.Stat(filepath.Join(from, rel)); err != nil {
+			t.Errorf("%s not back in the old root: %v", rel, err)
+		}
+	}
+	for _, rel := range []string{"release", "cache"} {
+		if _, err := os.Stat(filepath.Join(to, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s left in the new root", rel)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"HyLauncher/internal/config"
+)
+
+// Restart policies
+const (
+	RestartNever   = "never"
+	RestartOnCrash = "on-crash"
+	RestartAlways  = "always"
+)
+
+const (
+	defaultMaxRestarts   = 3
+	defaultRestartWindow = 5 * time.Minute
+	restartBaseDelay     = 2 * time.Second
+	restartMaxDelay      = 30 * time.Second
+)
+
+// RestartDecision tells what to do after the game exited
+type RestartDecision struct {
+	Restart bool
+	GaveUp  bool
+	Reason  string
+	Attempt int // restarts within the window, including this one
+	Max     int
+	Delay   time.Duration
+}
+
+// RestartTracker applies a profile's restart policy across one chain of launches
+type RestartTracker struct {
+	policy string
+	max    int
+	window time.Duration
+
+	mu       sync.Mutex
+	restarts []time.Time
+}
+
+// NewRestartTracker creates a tracker for a profile, limits default to 3 restarts in 5 minutes
+func NewRestartTracker(profile config.Profile) *RestartTracker {
+	t := &RestartTracker{
+		policy: profile.RestartPolicy,
+		max:    profile.MaxRestarts,
+		window: time.Duration(profile.RestartWindow) * time.Second,
+	}
+	if t.policy == "" {
+		t.policy = RestartNever
+	}
+	if t.max <= 0 {
+		t.max = defaultMaxRestarts
+	}
+	if t.window <= 0 {
+		t.window = defaultRestartWindow
+	}
+	return t
+}
+
+// Next decides whether to restart after an exit. Exits requested through the
+// launcher never restart.
+func (t *RestartTracker) Next(exit ExitInfo, stopped bool) RestartDecision {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	decision := RestartDecision{Max: t.max}
+
+	switch {
+	case stopped:
+		return decision
+	case t.policy == RestartOnCrash && exit.Crashed:
+		decision.Reason = "game crashed (" + exitDescription(exit) + ")"
+	case t.policy == RestartAlways:
+		decision.Reason = "game exited (" + exitDescription(exit) + ")"
+	default:
+		return decision
+	}
+
+	// Forget restarts that fell out of the window
+	now := time.Now()
+	recent := t.restarts[:0]
+	for _, at := range t.restarts {
+		if now.Sub(at) < t.window {
+			recent = append(recent, at)
+		}
+	}
+	t.restarts = recent
+
+	if len(t.restarts) >= t.max {
+		decision.GaveUp = true
+		decision.Attempt = len(t.restarts)
+		decision.Reason = fmt.Sprintf("%s, giving up after %d restarts in %s", decision.Reason, len(t.restarts), t.window)
+		return decision
+	}
+
+	t.restarts = append(t.restarts, now)
+	decision.Restart = true
+	decision.Attempt = len(t.restarts)
+
+	// Back off exponentially within the window
+	decision.Delay = restartBaseDelay << (decision.Attempt - 1)
+	if decision.Delay > restartMaxDelay {
+		decision.Delay = restartMaxDelay
+	}
+	return decision
+}
+
+// ValidateRestartPolicy checks the restart settings of a profile
+func ValidateRestartPolicy(profile config.Profile) error {
+	switch profile.RestartPolicy {
+	case "", RestartNever, RestartOnCrash, RestartAlways:
+	default:
+		return fmt.Errorf("unknown restart policy %q", profile.RestartPolicy)
+	}
+	if profile.MaxRestarts < 0 || profile.RestartWindow < 0 {
+		return fmt.Errorf("restart limits cannot be negative")
+	}
+	return nil
+}
+
+func exitDescription(exit ExitInfo) string {
+	if exit.Signal != "" {
+		return "signal " + exit.Signal
+	}
+	return fmt.Sprintf("exit code %d", exit.ExitCode)
+}
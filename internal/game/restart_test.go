@@ -0,0 +1,93 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"HyLauncher/internal/config"
+)
+
+func TestRestartTrackerPolicy(t *testing.T) {
+	crash := ExitInfo{ExitCode: 1, Crashed: true}
+	clean := ExitInfo{ExitCode: 0}
+
+	tests := []struct {
+		name    string
+		policy  string
+		exit    ExitInfo
+		stopped bool
+		restart bool
+	}{
+		{name: "default never restarts", policy: "", exit: crash},
+		{name: "never", policy: RestartNever, exit: crash},
+		{name: "on-crash after crash", policy: RestartOnCrash, exit: crash, restart: true},
+		{name: "on-crash after clean exit", policy: RestartOnCrash, exit: clean},
+		{name: "always after clean exit", policy: RestartAlways, exit: clean, restart: true},
+		{name: "always after stop", policy: RestartAlways, exit: clean, stopped: true},
+		{name: "on-crash after stop", policy: RestartOnCrash, exit: crash, stopped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewRestartTracker(config.Profile{RestartPolicy: tt.policy})
+			d := tracker.Next(tt.exit, tt.stopped)
+			if d.Restart != tt.restart || d.GaveUp {
+				t.Errorf("Next() = %+v, want restart %v", d, tt.restart)
+			}
+		})
+	}
+}
+
+func TestRestartTrackerBackoff(t *testing.T) {
+	tracker := NewRestartTracker(config.Profile{RestartPolicy: RestartAlways, MaxRestarts: 6})
+	want := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+
+	for i, delay := range want {
+		d := tracker.Next(ExitInfo{}, false)
+		if !d.Restart || d.Attempt != i+1 || d.Max != 6 || d.Delay != delay {
+			t.Fatalf("restart %d = %+v, want attempt %d after %s", i+1, d, i+1, delay)
+		}
+	}
+
+	d := tracker.Next(ExitInfo{}, false)
+	if d.Restart || !d.GaveUp || d.Attempt != 6 {
+		t.Errorf("restart past the limit = %+v, want to give up", d)
+	}
+}
+
+func TestRestartTrackerWindow(t *testing.T) {
+	tracker := NewRestartTracker(config.Profile{RestartPolicy: RestartOnCrash, MaxRestarts: 2, RestartWindow: 60})
+	crash := ExitInfo{ExitCode: 1, Crashed: true}
+
+	tracker.Next(crash, false)
+	tracker.Next(crash, false)
+	if d := tracker.Next(crash, false); !d.GaveUp {
+		t.Fatalf("third crash = %+v, want to give up", d)
+	}
+
+	// Restarts older than the window no longer count
+	tracker.restarts[0] = time.Now().Add(-2 * time.Minute)
+	d := tracker.Next(crash, false)
+	if !d.Restart || d.Attempt != 2 {
+		t.Errorf("crash after the window = %+v, want attempt 2", d)
+	}
+}
+
+func TestValidateRestartPolicy(t *testing.T) {
+	tests := []struct {
+		profile config.Profile
+		wantErr bool
+	}{
+		{profile: config.Profile{}},
+		{profile: config.Profile{RestartPolicy: RestartOnCrash, MaxRestarts: 5, RestartWindow: 600}},
+		{profile: config.Profile{RestartPolicy: "sometimes"}, wantErr: true},
+		{profile: config.Profile{RestartPolicy: RestartAlways, MaxRestarts: -1}, wantErr: true},
+		{profile: config.Profile{RestartPolicy: RestartAlways, RestartWindow: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateRestartPolicy(tt.profile); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateRestartPolicy(%+v) error = %v, wantErr %v", tt.profile, err, tt.wantErr)
+		}
+	}
+}
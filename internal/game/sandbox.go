@@ -0,0 +1,100 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// ErrSandboxUnavailable is returned when a sandboxed launch is not possible on this system
+var ErrSandboxUnavailable = errors.New("sandbox unavailable")
+
+// System paths the client needs to run, mounted read-only
+var sandboxSystemPaths = []string{"/usr", "/bin", "/sbin", "/lib", "/lib32", "/lib64", "/etc", "/sys", "/opt"}
+
+// GPU devices, only the ones that exist are mounted
+var sandboxDevices = []string{
+	"/dev/dri",
+	"/dev/nvidiactl",
+	"/dev/nvidia0",
+	"/dev/nvidia-modeset",
+	"/dev/nvidia-uvm",
+	"/dev/nvidia-uvm-tools",
+	"/dev/snd",
+}
+
+// sandboxMounts are the launcher directories visible inside the sandbox
+type sandboxMounts struct {
+	GameDir     string
+	UserDataDir string
+	JREDir      string
+}
+
+// sandboxCommand wraps a command in bubblewrap. Besides the read-only system paths
+// only the game directory, the profile's UserData and the JRE are mounted.
+func sandboxCommand(exe string, args []string, mounts sandboxMounts, network bool, environ *envBuilder) (string, []string, error) {
+	if runtime.GOOS != "linux" {
+		return "", nil, fmt.Errorf("%w: sandboxing is only supported on Linux", ErrSandboxUnavailable)
+	}
+
+	bwrap, err := exec.LookPath("bwrap")
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: bubblewrap (bwrap) is not installed, install it or disable the sandbox for this profile", ErrSandboxUnavailable)
+	}
+
+	sandboxed := append(sandboxArgs(mounts, network, environ), "--", exe)
+	sandboxed = append(sandboxed, args...)
+	return bwrap, sandboxed, nil
+}
+
+func sandboxArgs(mounts sandboxMounts, network bool, environ *envBuilder) []string {
+	args := []string{"--unshare-all"}
+	if network {
+		args = append(args, "--share-net")
+	}
+
+	for _, path := range sandboxSystemPaths {
+		args = append(args, "--ro-bind-try", path, path)
+	}
+
+	args = append(args, "--proc", "/proc", "--dev", "/dev", "--tmpfs", "/tmp")
+	for _, device := range sandboxDevices {
+		args = append(args, "--dev-bind-try", device, device)
+	}
+
+	// Display and audio sockets
+	args = append(args, "--ro-bind-try", "/tmp/.X11-unix", "/tmp/.X11-unix")
+	if xauth, ok := environ.Get("XAUTHORITY"); ok && xauth != "" {
+		args = append(args, "--ro-bind-try", xauth, xauth)
+	}
+	if runtimeDir, ok := environ.Get("XDG_RUNTIME_DIR"); ok && runtimeDir != "" {
+		args = append(args, "--dir", runtimeDir)
+		if display, ok := environ.Get("WAYLAND_DISPLAY"); ok && display != "" {
+			socket := display
+			if !filepath.IsAbs(socket) {
+				socket = filepath.Join(runtimeDir, display)
+			}
+			args = append(args, "--ro-bind-try", socket, socket)
+		}
+		for _, name := range []string{"pulse", "pipewire-0"} {
+			path := filepath.Join(runtimeDir, name)
+			args = append(args, "--ro-bind-try", path, path)
+		}
+	}
+
+	// HOME exists but is empty, the client keeps its data in the user directory
+	if home, ok := environ.Get("HOME"); ok && home != "" {
+		args = append(args, "--tmpfs", home)
+	}
+
+	args = append(args,
+		"--ro-bind", mounts.JREDir, mounts.JREDir,
+		"--bind", mounts.GameDir, mounts.GameDir,
+		"--bind", mounts.UserDataDir, mounts.UserDataDir,
+		"--chdir", mounts.GameDir,
+	)
+
+	return args
+}
@@ -0,0 +1,106 @@
+package game
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"HyLauncher/internal/config"
+	"HyLauncher/internal/env"
+	"HyLauncher/pkg/fileutil"
+)
+
+// SharedUserDataDir is the UserData directory used by profiles without isolation
+func SharedUserDataDir() string {
+	return filepath.Join(env.GetDefaultAppDir(), "UserData")
+}
+
+// IsolatedUserDataDir is the private UserData directory of a profile. It lives
+// in profiles/<id>/UserData rather than UserData/<id>: the shared UserData is
+// the game's --user-dir, so directories nested in it would show up as game data
+// and a migration would copy the shared directory into itself.
+func IsolatedUserDataDir(profileID string) string {
+	return filepath.Join(env.GetDefaultAppDir(), "profiles", profileID, "UserData")
+}
+
+// UserDataDir returns the UserData directory a profile launches with
+func UserDataDir(profile config.Profile) string {
+	if profile.IsolatedUserData && profile.ID != "" {
+		return IsolatedUserDataDir(profile.ID)
+	}
+	return SharedUserDataDir()
+}
+
+// MigrateUserData copies the shared UserData into the profile's isolated directory,
+// or moves it when move is set. Moved entries are renamed when possible and
+// copied otherwise, the sources of copies are only deleted once every entry has
+// arrived, and a failure returns the entries already moved to the shared directory.
+func MigrateUserData(profileID string, move bool) error {
+	if profileID == "" {
+		return fmt.Errorf("missing profile id")
+	}
+
+	shared := SharedUserDataDir()
+	target := IsolatedUserDataDir(profileID)
+
+	if entries, err := os.ReadDir(target); err == nil && len(entries) > 0 {
+		return fmt.Errorf("isolated data for this profile already exists at %s", target)
+	}
+
+	entries, err := os.ReadDir(shared)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return os.MkdirAll(target, 0755)
+		}
+		return fmt.Errorf("failed to read shared user data: %w", err)
+	}
+
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return fmt.Errorf("failed to create isolated user data directory: %w", err)
+	}
+
+	var moved []movedEntry
+	for _, entry := range entries {
+		src := filepath.Join(shared, entry.Name())
+		dst := filepath.Join(target, entry.Name())
+
+		if move {
+			if err := os.Rename(src, dst); err == nil {
+				moved = append(moved, movedEntry{src: src, dst: dst, renamed: true})
+				continue
+			}
+		}
+
+		if entry.IsDir() {
+			err = fileutil.CopyDir(src, dst)
+		} else {
+			err = fileutil.CopyFile(src, dst)
+		}
+		if err != nil {
+			_ = os.RemoveAll(dst)
+			return fmt.Errorf("failed to migrate %s: %w", entry.Name(), rollbackMove(moved, err))
+		}
+		moved = append(moved, movedEntry{src: src, dst: dst})
+	}
+
+	if move {
+		for _, entry := range moved {
+			if entry.renamed {
+				continue
+			}
+			if err := os.RemoveAll(entry.src); err != nil {
+				fmt.Printf("Warning: failed to remove %s after copying: %v\n", entry.src, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeleteIsolatedUserData removes a profile's isolated data directory
+func DeleteIsolatedUserData(profileID string) error {
+	if profileID == "" {
+		return fmt.Errorf("missing profile id")
+	}
+	return os.RemoveAll(filepath.Dir(IsolatedUserDataDir(profileID)))
+}
@@ -0,0 +1,96 @@
+//go:build linux || darwin
+
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// sharedUserData fills the shared UserData of a fake home with a file and a world
+func sharedUserData(t *testing.T) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	shared := SharedUserDataDir()
+	writeTestFile(t, filepath.Join(shared, "Settings.json"), "settings")
+	writeTestFile(t, filepath.Join(shared, "Saves", "world", "level.dat"), "world")
+	return shared
+}
+
+func TestMigrateUserData(t *testing.T) {
+	for _, move := range []bool{false, true} {
+		shared := sharedUserData(t)
+		if err := MigrateUserData("p1", move); err != nil {
+			t.Fatalf("MigrateUserData(move %v): %v", move, err)
+		}
+
+		target := IsolatedUserDataDir("p1")
+		for _, rel := range []string{"Settings.json", "Saves/world/level.dat"} {
+			if _, err := os.Stat(filepath.Join(target, rel)); err != nil {
+				t.Errorf("move %v: %s not migrated: %v", move, rel, err)
+			}
+			_, err := os.Stat(filepath.Join(shared, rel))
+			if move && !os.IsNotExist(err) {
+				t.Errorf("move: %s still in the shared UserData", rel)
+			}
+			if !move && err != nil {
+				t.Errorf("copy: %s removed from the shared UserData", rel)
+			}
+		}
+
+		if err := MigrateUserData("p1", move); err == nil {
+			t.Errorf("move %v: a second migration into existing data succeeded", move)
+		}
+	}
+}
+
+func TestMigrateUserDataRollback(t *testing.T) {
+	for _, move := range []bool{false, true} {
+		shared := sharedUserData(t)
+		target := IsolatedUserDataDir("p1")
+
+		if move {
+			// Renames always succeed on one filesystem, put the target on another
+			// so every entry is copied and the broken one fails
+			other, err := os.MkdirTemp("/dev/shm", "userdata")
+			if err != nil || sameDevice(other, shared) {
+				t.Skip("no second filesystem to move user data to")
+			}
+			t.Cleanup(func() { os.RemoveAll(other) })
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Symlink(other, target); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Sorted last, fails once the other entries have been migrated
+		if err := os.Symlink("missing", filepath.Join(shared, "zz-broken")); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := MigrateUserData("p1", move); err == nil {
+			t.Fatalf("move %v: migrating a broken entry succeeded", move)
+		}
+
+		if entries, _ := os.ReadDir(target); len(entries) != 0 {
+			t.Errorf("move %v: %d entries left in the isolated UserData", move, len(entries))
+		}
+		for _, rel := range []string{"Settings.json", "Saves/world/level.dat"} {
+			if _, err := os.Stat(filepath.Join(shared, rel)); err != nil {
+				t.Errorf("move %v: %s lost from the shared UserData: %v", move, rel, err)
+			}
+		}
+	}
+}
+
+func sameDevice(a, b string) bool {
+	var sa, sb syscall.Stat_t
+	if syscall.Stat(a, &sa) != nil || syscall.Stat(b, &sb) != nil {
+		return true
+	}
+	return sa.Dev == sb.Dev
+}
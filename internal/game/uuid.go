@@ -1,12 +1,94 @@
 package game
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"HyLauncher/internal/config"
+
 	"github.com/google/uuid"
 )
 
+// UUID strategies a profile can play with
+const (
+	UUIDModeProfile = "profile" // the profile ID, stays the same across renames
+	UUIDModeOffline = "offline" // derived from the nickname, what offline servers expect
+	UUIDModeCustom  = "custom"  // a fixed UUID entered by the user
+)
+
+// UUIDChange describes how saving a profile would change the player's identity
+type UUIDChange struct {
+	Previous string   `json:"previous"`
+	Next     string   `json:"next"`
+	Changed  bool     `json:"changed"`
+	Worlds   []string `json:"worlds"` // worlds that may no longer recognize the player
+}
+
 func OfflineUUID(nick string) uuid.UUID {
 	data := []byte("OfflinePlayer:" + strings.TrimSpace(nick))
 	return uuid.NewMD5(uuid.Nil, data)
 }
+
+// ResolveUUID returns the UUID a profile plays with under the given nickname
+func ResolveUUID(profile config.Profile, nick string) (string, error) {
+	switch profile.UUIDMode {
+	case "", UUIDModeProfile:
+		return profile.ID, nil
+	case UUIDModeOffline:
+		if strings.TrimSpace(nick) == "" {
+			return "", fmt.Errorf("a nickname is required to derive the UUID")
+		}
+		return OfflineUUID(nick).String(), nil
+	case UUIDModeCustom:
+		id, err := uuid.Parse(strings.TrimSpace(profile.CustomUUID))
+		if err != nil {
+			return "", fmt.Errorf("invalid custom UUID: %w", err)
+		}
+		return id.String(), nil
+	default:
+		return "", fmt.Errorf("unknown UUID mode %q", profile.UUIDMode)
+	}
+}
+
+// CheckUUIDChange compares the identity a profile last played with against the one
+// the updated profile would use, listing the worlds the old identity has data in
+func CheckUUIDChange(current, updated config.Profile) (UUIDChange, error) {
+	next, err := ResolveUUID(updated, updated.Name)
+	if err != nil {
+		return UUIDChange{}, err
+	}
+
+	previous := current.LastUUID
+	if previous == "" {
+		if previous, err = ResolveUUID(current, current.Name); err != nil {
+			previous = current.ID
+		}
+	}
+
+	change := UUIDChange{
+		Previous: previous,
+		Next:     next,
+		Changed:  !strings.EqualFold(previous, next),
+	}
+	if change.Changed {
+		change.Worlds = listWorlds(UserDataDir(current))
+	}
+	return change, nil
+}
+
+func listWorlds(userDataDir string) []string {
+	entries, err := os.ReadDir(filepath.Join(userDataDir, "Saves"))
+	if err != nil {
+		return nil
+	}
+
+	var worlds []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			worlds = append(worlds, entry.Name())
+		}
+	}
+	return worlds
+}
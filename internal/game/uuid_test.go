@@ -0,0 +1,82 @@
+package game
+
+import (
+	"testing"
+
+	"HyLauncher/internal/config"
+)
+
+func TestResolveUUID(t *testing.T) {
+	const profileID = "0d7f6c52-5a4e-4b0e-9d1e-6f1f2f3a4b5c"
+	offline := OfflineUUID("Steve").String()
+
+	tests := []struct {
+		name    string
+		profile config.Profile
+		nick    string
+		want    string
+		wantErr bool
+	}{
+		{name: "default is profile ID", profile: config.Profile{ID: profileID}, nick: "Steve", want: profileID},
+		{name: "profile mode", profile: config.Profile{ID: profileID, UUIDMode: UUIDModeProfile}, nick: "Steve", want: profileID},
+		{name: "offline mode", profile: config.Profile{ID: profileID, UUIDMode: UUIDModeOffline}, nick: "Steve", want: offline},
+		{name: "offline mode trims nick", profile: config.Profile{ID: profileID, UUIDMode: UUIDModeOffline}, nick: " Steve ", want: offline},
+		{name: "offline mode without nick", profile: config.Profile{ID: profileID, UUIDMode: UUIDModeOffline}, nick: " ", wantErr: true},
+		{name: "custom mode normalized", profile: config.Profile{UUIDMode: UUIDModeCustom, CustomUUID: " 0D7F6C52-5A4E-4B0E-9D1E-6F1F2F3A4B5C "}, want: profileID},
+		{name: "custom mode invalid", profile: config.Profile{UUIDMode: UUIDModeCustom, CustomUUID: "nope"}, wantErr: true},
+		{name: "unknown mode", profile: config.Profile{UUIDMode: "random"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveUUID(tt.profile, tt.nick)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveUUID error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveUUID = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckUUIDChange(t *testing.T) {
+	const profileID = "0d7f6c52-5a4e-4b0e-9d1e-6f1f2f3a4b5c"
+	base := config.Profile{ID: profileID, Name: "Steve"}
+
+	offline := base
+	offline.UUIDMode = UUIDModeOffline
+
+	renamedOffline := offline
+	renamedOffline.Name = "Alex"
+
+	renamedProfile := base
+	renamedProfile.Name = "Alex"
+
+	played := offline
+	played.LastUUID = profileID
+
+	tests := []struct {
+		name    string
+		current config.Profile
+		updated config.Profile
+		changed bool
+	}{
+		{name: "rename with profile ID", current: base, updated: renamedProfile, changed: false},
+		{name: "rename in offline mode", current: offline, updated: renamedOffline, changed: true},
+		{name: "switch to offline mode", current: base, updated: offline, changed: true},
+		{name: "last launch wins over the stored mode", current: played, updated: renamedProfile, changed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := CheckUUIDChange(tt.current, tt.updated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if change.Changed != tt.changed {
+				t.Errorf("Changed = %v (%s -> %s), want %v", change.Changed, change.Previous, change.Next, tt.changed)
+			}
+		})
+	}
+}
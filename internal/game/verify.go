@@ -0,0 +1,442 @@
+package game
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"HyLauncher/internal/env"
+	"HyLauncher/internal/patch"
+	"HyLauncher/internal/progress"
+)
+
+// Entries of an install directory that are not part of the build
+var manifestIgnored = map[string]bool{
+	".cache": true, // online fix downloads
+}
+
+// Manifest records the files of an install directory right after it was installed
+type Manifest struct {
+	Version   int                      `json:"version"`
+	OnlineFix bool                     `json:"onlineFix,omitempty"` // recorded after the online fix replaced its files
+	Created   time.Time                `json:"created"`
+	Files     map[string]ManifestEntry `json:"files"` // slash separated path relative to the install dir
+}
+
+// ManifestEntry is one file of a manifest, Link is set for symlinks instead of a hash
+type ManifestEntry struct {
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+	Exec   bool   `json:"exec,omitempty"`
+	Link   string `json:"link,omitempty"`
+}
+
+// VerifyResult lists what differs between an install directory and its build
+type VerifyResult struct {
+	Channel  string   `json:"channel"`
+	Dir      string   `json:"dir"`
+	Version  int      `json:"version"`
+	Checked  int      `json:"checked"`
+	Missing  []string `json:"missing"`
+	Changed  []string `json:"changed"`
+	Extra    []string `json:"extra"`
+	Removed  []string `json:"removed"` // extra files removed on request
+	Repaired bool     `json:"repaired"`
+}
+
+// Intact reports whether nothing is missing, changed or extra
+func (r *VerifyResult) Intact() bool {
+	return len(r.Missing) == 0 && len(r.Changed) == 0 && len(r.Extra) == 0
+}
+
+func manifestPath(channel, dir string) string {
+	return filepath.Join(env.GetChannelDir(channel), "manifests", dir+".json")
+}
+
+func loadManifest(channel, dir string) (*Manifest, error) {
+	data, err := os.ReadFile(manifestPath(channel, dir))
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]ManifestEntry)
+	}
+	return &m, nil
+}
+
+func saveManifest(channel, dir string, m *Manifest) error {
+	path := manifestPath(channel, dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// RecordManifest hashes an install directory and stores it as the reference for
+// later checks. onlineFix tells whether the online fix was applied to it.
+func RecordManifest(ctx context.Context, channel, dir string, version int, onlineFix bool, reporter *progress.Reporter) error {
+	scaler := progress.NewScaler(reporter, progress.StageVerify, 0, 100)
+	m, err := buildManifest(ctx, InstallDir(channel, dir), version, scaler)
+	if err != nil {
+		return err
+	}
+	m.OnlineFix = onlineFix
+	return saveManifest(channel, dir, m)
+}
+
+// usesOnlineFix reports whether installs carry the online fix on this system
+func usesOnlineFix(enableOnlineFix bool) bool {
+	return runtime.GOOS == "windows" && enableOnlineFix
+}
+
+// VerifyInstall checks an install directory against its manifest. When
+// repair is set, missing and changed files are copied from a fresh copy of
+// the full build and the manifest is rewritten. Extra files are only removed
+// when listed in removeExtra, after the user confirmed them.
+//
+// Installs without a manifest are compared against the full build, which is
+// taken from the patch cache or downloaded, with the online fix applied when
+// it is enabled.
+func VerifyInstall(ctx context.Context, channel, dir string, repair bool, removeExtra []string, enableOnlineFix bool, reporter *progress.Reporter) (*VerifyResult, error) {
+	if err := beginInstall(); err != nil {
+		return nil, err
+	}
+	defer endInstall()
+
+	installDir := InstallDir(channel, dir)
+	if _, err := os.Stat(installDir); err != nil {
+		return nil, fmt.Errorf("version %s is not installed", dir)
+	}
+
+	version := InstalledVersionOf(channel, dir)
+	if version <= 0 {
+		return nil, fmt.Errorf("the installed build of %s is unknown, reinstall it instead", dir)
+	}
+
+	result := &VerifyResult{Channel: channel, Dir: dir, Version: version}
+	onlineFix := usesOnlineFix(enableOnlineFix)
+
+	// The full build is only fetched when there is no manifest or something needs repair
+	sourceDir := filepath.Join(env.GetCacheDir(), "repair", channel)
+	defer os.RemoveAll(sourceDir)
+	sourceReady := false
+	prepareSource := func() error {
+		if sourceReady {
+			return nil
+		}
+		if err := stageFullBuild(ctx, channel, version, sourceDir, reporter); err != nil {
+			return err
+		}
+		// The install carries the online fix, so must the reference
+		if onlineFix {
+			if err := ApplyOnlineFixWindows(ctx, sourceDir, reporter); err != nil {
+				return fmt.Errorf("failed to apply online fix: %w", err)
+			}
+		}
+		sourceReady = true
+		return nil
+	}
+
+	manifest, err := loadManifest(channel, dir)
+	if err != nil || manifest.Version != version || manifest.OnlineFix != onlineFix {
+		if reporter != nil {
+			reporter.Report(progress.StageVerify, 0, fmt.Sprintf("No file manifest for version %d, fetching the full build", version))
+		}
+		if err := prepareSource(); err != nil {
+			return nil, err
+		}
+		manifest, err = buildManifest(ctx, sourceDir, version, progress.NewScaler(reporter, progress.StageVerify, 0, 100))
+		if err != nil {
+			return nil, err
+		}
+		manifest.OnlineFix = onlineFix
+		// The build itself is the reference for later checks
+		if err := saveManifest(channel, dir, manifest); err != nil {
+			fmt.Printf("Warning: failed to record manifest: %v\n", err)
+		}
+	}
+
+	if err := compareManifest(ctx, installDir, manifest, result, progress.NewScaler(reporter, progress.StageVerify, 0, 100)); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Verified %s/%s: %d files, %d missing, %d changed, %d extra\n",
+		channel, dir, result.Checked, len(result.Missing), len(result.Changed), len(result.Extra))
+
+	if result.Intact() || !repair {
+		if reporter != nil {
+			reporter.Report(progress.StageComplete, 100, verifySummary(result))
+		}
+		return result, nil
+	}
+
+	broken := append(append([]string{}, result.Missing...), result.Changed...)
+	if len(broken) > 0 {
+		if err := prepareSource(); err != nil {
+			return nil, err
+		}
+	}
+	for i, rel := range broken {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if reporter != nil {
+			reporter.ReportWithFile(progress.StagePatch, float64(i)*100/float64(len(broken)), "Repairing files...", rel)
+		}
+		if err := restoreFile(sourceDir, installDir, rel); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				fmt.Printf("Warning: %s is not part of version %d, skipping\n", rel, version)
+				continue
+			}
+			return nil, fmt.Errorf("failed to repair %s: %w", rel, err)
+		}
+	}
+
+	// Only what verification found extra, a stale list cannot reach other files
+	confirmed := make(map[string]bool, len(removeExtra))
+	for _, rel := range removeExtra {
+		confirmed[rel] = true
+	}
+	for _, rel := range result.Extra {
+		if !confirmed[rel] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(installDir, filepath.FromSlash(rel))); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", rel, err)
+		}
+		result.Removed = append(result.Removed, rel)
+	}
+
+	// The manifest stays the reference, kept extra files must still show up as extra
+	result.Repaired = true
+	if reporter != nil {
+		reporter.Report(progress.StageComplete, 100, verifySummary(result))
+	}
+	return result, nil
+}
+
+func verifySummary(r *VerifyResult) string {
+	switch {
+	case r.Intact():
+		return fmt.Sprintf("All %d files are intact", r.Checked)
+	case r.Repaired:
+		return fmt.Sprintf("Repaired %d files, removed %d of %d extra", len(r.Missing)+len(r.Changed), len(r.Removed), len(r.Extra))
+	default:
+		return fmt.Sprintf("%d missing, %d changed, %d extra files", len(r.Missing), len(r.Changed), len(r.Extra))
+	}
+}
+
+// stageFullBuild applies the full build of a version into dir
+func stageFullBuild(ctx context.Context, channel string, version int, dir string, reporter *progress.Reporter) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	step := patch.PatchStep{From: 0, To: version, URL: patch.PatchURL(channel, 0, version)}
+	pwrPath, err := patch.DownloadPatch(ctx, channel, step, progress.NewScaler(reporter, progress.StagePWR, 0, 100))
+	if err != nil {
+		return fmt.Errorf("failed to download game build: %w", err)
+	}
+
+	if err := patch.ApplyPatchTo(ctx, channel, pwrPath, dir, progress.NewScaler(reporter, progress.StagePatch, 0, 100)); err != nil {
+		return fmt.Errorf("failed to unpack game build: %w", err)
+	}
+
+	if err := patch.TrimCache(); err != nil {
+		fmt.Printf("Warning: failed to trim patch cache: %v\n", err)
+	}
+	return nil
+}
+
+// walkInstall visits the files and symlinks of an install directory
+func walkInstall(root string, fn func(rel string, path string, d fs.DirEntry) error) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if manifestIgnored[rel] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		return fn(rel, path, d)
+	})
+}
+
+func buildManifest(ctx context.Context, root string, version int, scaler *progress.Scaler) (*Manifest, error) {
+	m := &Manifest{Version: version, Created: time.Now(), Files: make(map[string]ManifestEntry)}
+
+	total := dirSize(root)
+	var done int64
+
+	err := walkInstall(root, func(rel, path string, d fs.DirEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		entry, err := hashEntry(path, d)
+		if err != nil {
+			return err
+		}
+		m.Files[rel] = entry
+
+		done += entry.Size
+		if total > 0 {
+			scaler.ReportWithFile(progress.StageVerify, float64(done)*100/float64(total), "Hashing game files...", rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func compareManifest(ctx context.Context, root string, m *Manifest, result *VerifyResult, scaler *progress.Scaler) error {
+	var total, done int64
+	for _, entry := range m.Files {
+		total += entry.Size
+	}
+
+	seen := make(map[string]bool, len(m.Files))
+	err := walkInstall(root, func(rel, path string, d fs.DirEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		want, ok := m.Files[rel]
+		if !ok {
+			result.Extra = append(result.Extra, rel)
+			return nil
+		}
+		seen[rel] = true
+		result.Checked++
+
+		got, err := hashEntry(path, d)
+		if err != nil || !sameEntry(got, want) {
+			result.Changed = append(result.Changed, rel)
+		}
+
+		done += want.Size
+		if total > 0 {
+			scaler.ReportWithFile(progress.StageVerify, float64(done)*100/float64(total), "Verifying game files...", rel)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for rel := range m.Files {
+		if !seen[rel] {
+			result.Missing = append(result.Missing, rel)
+		}
+	}
+
+	sort.Strings(result.Missing)
+	sort.Strings(result.Changed)
+	sort.Strings(result.Extra)
+	return nil
+}
+
+func sameEntry(got, want ManifestEntry) bool {
+	if got.Link != want.Link || got.Size != want.Size || got.SHA256 != want.SHA256 {
+		return false
+	}
+	// The executable bit means nothing on Windows
+	return runtime.GOOS == "windows" || got.Exec == want.Exec
+}
+
+func hashEntry(path string, d fs.DirEntry) (ManifestEntry, error) {
+	if d.Type()&fs.ModeSymlink != 0 {
+		link, err := os.Readlink(path)
+		if err != nil {
+			return ManifestEntry{}, err
+		}
+		return ManifestEntry{Link: link}, nil
+	}
+
+	info, err := d.Info()
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+
+	return ManifestEntry{
+		Size:   n,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+		Exec:   info.Mode().Perm()&0111 != 0,
+	}, nil
+}
+
+// restoreFile copies one file of the fresh build over the install
+func restoreFile(sourceDir, installDir, rel string) error {
+	src := filepath.Join(sourceDir, filepath.FromSlash(rel))
+	dst := filepath.Join(installDir, filepath.FromSlash(rel))
+
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	// A directory or link in the way is replaced as well
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(link, dst)
+	}
+	return copyFileMode(src, dst, info.Mode().Perm(), func(int64) {})
+}
@@ -0,0 +1,186 @@
+package game
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"HyLauncher/internal/env"
+	"HyLauncher/internal/progress"
+)
+
+func TestCompareManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(t *testing.T, root string)
+		checked int
+		missing []string
+		changed []string
+		extra   []string
+	}{
+		{name: "intact", checked: 3},
+		{
+			name:    "missing file",
+			edit:    func(t *testing.T, root string) { os.Remove(filepath.Join(root, "Server", "HytaleServer.jar")) },
+			checked: 2,
+			missing: []string{"Server/HytaleServer.jar"},
+		},
+		{
+			name:    "changed content",
+			edit:    func(t *testing.T, root string) { writeTestFile(t, filepath.Join(root, "Client", "Data.zip"), "DATA") },
+			checked: 3,
+			changed: []string{"Client/Data.zip"},
+		},
+		{
+			name: "changed size",
+			edit: func(t *testing.T, root string) {
+				writeTestFile(t, filepath.Join(root, "Client", "Data.zip"), "data, modded")
+			},
+			checked: 3,
+			changed: []string{"Client/Data.zip"},
+		},
+		{
+			name: "extra files",
+			edit: func(t *testing.T, root string) {
+				writeTestFile(t, filepath.Join(root, "Client", "mods", "a.jar"), "mod")
+				writeTestFile(t, filepath.Join(root, "notes.txt"), "x")
+			},
+			checked: 3,
+			extra:   []string{"Client/mods/a.jar", "notes.txt"},
+		},
+		{
+			name: "ignored cache",
+			edit: func(t *testing.T, root string) {
+				writeTestFile(t, filepath.Join(root, ".cache", "online-fix.zip"), "zip")
+			},
+			checked: 3,
+		},
+		{
+			name: "file replaced by a directory",
+			edit: func(t *testing.T, root string) {
+				os.Remove(filepath.Join(root, "Client", "Data.zip"))
+				writeTestFile(t, filepath.Join(root, "Client", "Data.zip", "inner"), "x")
+			},
+			checked: 2,
+			missing: []string{"Client/Data.zip"},
+			extra:   []string{"Client/Data.zip/inner"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTestFile(t, filepath.Join(root, "Client", "HytaleClient"), "client")
+			writeTestFile(t, filepath.Join(root, "Client", "Data.zip"), "data")
+			writeTestFile(t, filepath.Join(root, "Server", "HytaleServer.jar"), "server")
+
+			scaler := progress.NewScaler(nil, progress.StageVerify, 0, 100)
+			m, err := buildManifest(context.Background(), root, 5, scaler)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.edit != nil {
+				tt.edit(t, root)
+			}
+
+			result := &VerifyResult{}
+			if err := compareManifest(context.Background(), root, m, result, scaler); err != nil {
+				t.Fatal(err)
+			}
+			if result.Checked != tt.checked ||
+				!reflect.DeepEqual(result.Missing, tt.missing) ||
+				!reflect.DeepEqual(result.Changed, tt.changed) ||
+				!reflect.DeepEqual(result.Extra, tt.extra) {
+				t.Errorf("result = %d checked, missing %q, changed %q, extra %q, want %d, %q, %q, %q",
+					result.Checked, result.Missing, result.Changed, result.Extra, tt.checked, tt.missing, tt.changed, tt.extra)
+			}
+		})
+	}
+}
+
+func TestCompareManifestSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on Windows")
+	}
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "lib", "libfoo.so.1"), "lib")
+	if err := os.Symlink("libfoo.so.1", filepath.Join(root, "lib", "libfoo.so")); err != nil {
+		t.Fatal(err)
+	}
+
+	scaler := progress.NewScaler(nil, progress.StageVerify, 0, 100)
+	m, err := buildManifest(context.Background(), root, 1, scaler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Files["lib/libfoo.so"].Link != "libfoo.so.1" {
+		t.Fatalf("manifest entry = %+v, want a link", m.Files["lib/libfoo.so"])
+	}
+
+	os.Remove(filepath.Join(root, "lib", "libfoo.so"))
+	os.Symlink("elsewhere", filepath.Join(root, "lib", "libfoo.so"))
+
+	result := &VerifyResult{}
+	if err := compareManifest(context.Background(), root, m, result, scaler); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"lib/libfoo.so"}; !reflect.DeepEqual(result.Changed, want) {
+		t.Errorf("changed = %q, want %q", result.Changed, want)
+	}
+}
+
+func TestRepairKeepsUnconfirmedExtras(t *testing.T) {
+	env.SetInstallDir(t.TempDir())
+	defer env.SetInstallDir("")
+
+	client := "Client/HytaleClient"
+	if runtime.GOOS == "windows" {
+		client += ".exe"
+	}
+	installDir := InstallDir("release", "3")
+	writeTestFile(t, filepath.Join(installDir, filepath.FromSlash(client)), "client")
+	if err := RecordInstalledVersion("release", "3", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordManifest(context.Background(), "release", "3", 3, usesOnlineFix(true), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	writeTestFile(t, filepath.Join(installDir, "mods", "a.jar"), "mod")
+	writeTestFile(t, filepath.Join(installDir, "mods", "b.jar"), "mod")
+
+	// Repair alone removes nothing
+	result, err := VerifyInstall(context.Background(), "release", "3", true, nil, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"mods/a.jar", "mods/b.jar"}; !reflect.DeepEqual(result.Extra, want) || len(result.Removed) != 0 {
+		t.Fatalf("extra %q removed %q, want %q kept", result.Extra, result.Removed, want)
+	}
+
+	// Only confirmed extra files go, files of the build are never on the list
+	result, err = VerifyInstall(context.Background(), "release", "3", true, []string{"mods/a.jar", client}, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"mods/a.jar"}; !reflect.DeepEqual(result.Removed, want) {
+		t.Errorf("removed %q, want %q", result.Removed, want)
+	}
+	for rel, kept := range map[string]bool{"mods/a.jar": false, "mods/b.jar": true, client: true} {
+		if _, err := os.Stat(filepath.Join(installDir, filepath.FromSlash(rel))); (err == nil) != kept {
+			t.Errorf("%s kept = %v, want %v", rel, err == nil, kept)
+		}
+	}
+
+	// The kept file is still reported on the next check
+	result, err = VerifyInstall(context.Background(), "release", "3", false, nil, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"mods/b.jar"}; !reflect.DeepEqual(result.Extra, want) {
+		t.Errorf("extra after repair = %q, want %q", result.Extra, want)
+	}
+}
@@ -0,0 +1,221 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"HyLauncher/internal/env"
+	"HyLauncher/internal/patch"
+)
+
+// LatestDir is the install directory that follows the newest build of a channel
+const LatestDir = "latest"
+
+const versionsFile = "versions.json"
+
+var registryMu sync.Mutex
+
+// InstalledVersion is a game build installed side by side with the others
+type InstalledVersion struct {
+	Channel     string    `json:"channel"`
+	Version     int       `json:"version"`
+	Dir         string    `json:"dir"`
+	Path        string    `json:"path"`
+	Size        int64     `json:"size"`
+	InstalledAt time.Time `json:"installedAt"`
+	Active      bool      `json:"active"`
+}
+
+// registryEntry is what versions.json stores for each install directory
+type registryEntry struct {
+	Version     int       `json:"version"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// VersionDir returns the install directory name of a build, 0 meaning latest
+func VersionDir(version int) string {
+	if version > 0 {
+		return strconv.Itoa(version)
+	}
+	return LatestDir
+}
+
+// ListInstalledVersions returns every build installed for a channel, newest first.
+// Builds installed before the registry existed are picked up from disk.
+func ListInstalledVersions(channel string, activeDir string) ([]InstalledVersion, error) {
+	registryMu.Lock()
+	entries, err := loadRegistry(channel)
+	registryMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	gameRoot := filepath.Dir(InstallDir(channel, LatestDir))
+	dirs, err := os.ReadDir(gameRoot)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var versions []InstalledVersion
+	for _, d := range dirs {
+		// Dot directories are full builds still being staged
+		if !d.IsDir() || strings.HasPrefix(d.Name(), ".") || !isClientInstalled(InstallDir(channel, d.Name())) {
+			continue
+		}
+
+		entry, ok := entries[d.Name()]
+		if !ok {
+			entry = legacyEntry(channel, d.Name())
+		}
+
+		path := InstallDir(channel, d.Name())
+		versions = append(versions, InstalledVersion{
+			Channel:     channel,
+			Version:     entry.Version,
+			Dir:         d.Name(),
+			Path:        path,
+			Size:        dirSize(path),
+			InstalledAt: entry.InstalledAt,
+			Active:      d.Name() == activeDir,
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].Dir == LatestDir || versions[j].Dir == LatestDir {
+			return versions[i].Dir == LatestDir
+		}
+		return versions[i].Version > versions[j].Version
+	})
+
+	return versions, nil
+}
+
+// InstalledVersionOf returns the build installed in a directory, 0 if there is none
+func InstalledVersionOf(channel, dir string) int {
+	if !isClientInstalled(InstallDir(channel, dir)) {
+		return 0
+	}
+
+	registryMu.Lock()
+	entries, err := loadRegistry(channel)
+	registryMu.Unlock()
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	if entry, ok := entries[dir]; ok {
+		return entry.Version
+	}
+	return legacyEntry(channel, dir).Version
+}
+
+// RecordInstalledVersion stores the build now installed in a directory
+func RecordInstalledVersion(channel, dir string, version int) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	entries, err := loadRegistry(channel)
+	if err != nil {
+		entries = make(map[string]registryEntry)
+	}
+
+	entries[dir] = registryEntry{Version: version, InstalledAt: time.Now()}
+	return saveRegistry(channel, entries)
+}
+
+// DeleteVersion removes an installed build and its registry entry
+func DeleteVersion(channel, dir string) error {
+	if dir == "" || dir != filepath.Base(dir) || dir == "." || dir == ".." {
+		return fmt.Errorf("invalid version directory: %q", dir)
+	}
+
+	if err := beginInstall(); err != nil {
+		return err
+	}
+	defer endInstall()
+
+	path := InstallDir(channel, dir)
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("version %s is not installed", dir)
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	_ = os.Remove(manifestPath(channel, dir))
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	entries, err := loadRegistry(channel)
+	if err != nil {
+		return nil
+	}
+	delete(entries, dir)
+	return saveRegistry(channel, entries)
+}
+
+func registryPath(channel string) string {
+	return filepath.Join(env.GetChannelDir(channel), versionsFile)
+}
+
+func loadRegistry(channel string) (map[string]registryEntry, error) {
+	entries := make(map[string]registryEntry)
+
+	data, err := os.ReadFile(registryPath(channel))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return entries, err
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return make(map[string]registryEntry), fmt.Errorf("version registry is corrupted: %w", err)
+	}
+	return entries, nil
+}
+
+func saveRegistry(channel string, entries map[string]registryEntry) error {
+	path := registryPath(channel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// legacyEntry guesses the build of a directory installed before the registry existed
+func legacyEntry(channel, dir string) registryEntry {
+	entry := registryEntry{}
+	if info, err := os.Stat(InstallDir(channel, dir)); err == nil {
+		entry.InstalledAt = info.ModTime()
+	}
+
+	if dir == LatestDir {
+		entry.Version, _ = strconv.Atoi(patch.GetLocalVersion(channel))
+	} else {
+		entry.Version, _ = strconv.Atoi(dir)
+	}
+	return entry
+}
+
+func isClientInstalled(gameDir string) bool {
+	gameClient := "HytaleClient"
+	if runtime.GOOS == "windows" {
+		gameClient += ".exe"
+	}
+	_, err := os.Stat(filepath.Join(gameDir, "Client", gameClient))
+	return err == nil
+}
@@ -2,8 +2,6 @@ package game
 
 import (
 	"os"
-	"os/exec"
-	"runtime"
 )
 
 func isWayland() bool {
@@ -12,11 +10,3 @@ func isWayland() bool {
 
 	return waylandDisplay != "" || sessionType == "wayland"
 }
-
-func setSDLVideoDriver(cmd *exec.Cmd) {
-	if runtime.GOOS == "linux" && isWayland() {
-		env := os.Environ()
-		env = append(env, "SDL_VIDEODRIVER=wayland")
-		cmd.Env = env
-	}
-}
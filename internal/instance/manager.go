@@ -0,0 +1,225 @@
+package instance
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	"HyLauncher/internal/platform"
+
+	"github.com/google/uuid"
+)
+
+// Kind is the type of a tracked process
+type Kind string
+
+const (
+	KindGame   Kind = "game"
+	KindServer Kind = "server"
+)
+
+// Info describes a running process and is safe to send to the frontend
+type Info struct {
+	ID          string    `json:"id"`
+	Kind        Kind      `json:"kind"`
+	ProfileID   string    `json:"profileId"`
+	ProfileName string    `json:"profileName"`
+	Channel     string    `json:"channel"`
+	Version     string    `json:"version"`
+	SessionID   string    `json:"sessionId,omitempty"`
+	PID         int       `json:"pid"`
+	StartedAt   time.Time `json:"startedAt"`
+}
+
+// Instance is a single tracked process
+type Instance struct {
+	info    Info
+	cmd     *exec.Cmd
+	manager *Manager
+	done    chan struct{}
+
+	mu       sync.Mutex
+	stopping bool
+}
+
+// Manager keeps track of every game and server process started by the launcher
+type Manager struct {
+	mu        sync.RWMutex
+	instances map[string]*Instance
+}
+
+// NewManager creates an empty instance manager
+func NewManager() *Manager {
+	return &Manager{
+		instances: make(map[string]*Instance),
+	}
+}
+
+// Track registers an already started process. The caller must call Wait on the
+// returned instance, which removes it from the manager once the process exits.
+func (m *Manager) Track(cmd *exec.Cmd, info Info) *Instance {
+	info.ID = uuid.New().String()
+	if cmd.Process != nil {
+		info.PID = cmd.Process.Pid
+	}
+	if info.StartedAt.IsZero() {
+		info.StartedAt = time.Now()
+	}
+
+	inst := &Instance{
+		info:    info,
+		cmd:     cmd,
+		manager: m,
+		done:    make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	m.instances[info.ID] = inst
+	m.mu.Unlock()
+
+	return inst
+}
+
+// Get returns a running instance by ID
+func (m *Manager) Get(id string) (*Instance, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	inst, ok := m.instances[id]
+	return inst, ok
+}
+
+// List returns all running instances, oldest first
+func (m *Manager) List() []Info {
+	m.mu.RLock()
+	infos := make([]Info, 0, len(m.instances))
+	for _, inst := range m.instances {
+		infos = append(infos, inst.info)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].StartedAt.Before(infos[j].StartedAt)
+	})
+
+	return infos
+}
+
+// ListKind returns running instances of one kind
+func (m *Manager) ListKind(kind Kind) []Info {
+	var infos []Info
+	for _, info := range m.List() {
+		if info.Kind == kind {
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
+// Stop asks an instance to exit and kills it once the grace period is over
+func (m *Manager) Stop(id string, grace time.Duration) error {
+	inst, ok := m.Get(id)
+	if !ok {
+		return fmt.Errorf("instance %s is not running", id)
+	}
+	return inst.Stop(grace)
+}
+
+// Kill terminates an instance immediately
+func (m *Manager) Kill(id string) error {
+	inst, ok := m.Get(id)
+	if !ok {
+		return fmt.Errorf("instance %s is not running", id)
+	}
+	return inst.Kill()
+}
+
+func (m *Manager) remove(id string) {
+	m.mu.Lock()
+	delete(m.instances, id)
+	m.mu.Unlock()
+}
+
+// Info returns a snapshot of the instance description
+func (i *Instance) Info() Info {
+	return i.info
+}
+
+// Done is closed once the process has exited
+func (i *Instance) Done() <-chan struct{} {
+	return i.done
+}
+
+// Stopping reports whether the launcher asked the process to exit
+func (i *Instance) Stopping() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.stopping
+}
+
+// Wait blocks until the process exits and removes it from the manager
+func (i *Instance) Wait() error {
+	err := i.cmd.Wait()
+	i.manager.remove(i.info.ID)
+	close(i.done)
+	return err
+}
+
+// ProcessState returns the exit state, only valid after Wait returns
+func (i *Instance) ProcessState() *os.ProcessState {
+	return i.cmd.ProcessState
+}
+
+// Stop asks the whole process group to exit. Whatever is still running after
+// the grace period, including children that outlived the main process, is killed.
+func (i *Instance) Stop(grace time.Duration) error {
+	if !i.markStopping() {
+		// A stop is already in progress
+		return nil
+	}
+
+	pid := i.cmd.Process.Pid
+	if err := platform.TerminateProcessGroup(pid); err != nil {
+		fmt.Printf("Graceful stop of %s failed, killing it: %v\n", i.info.ID, err)
+		return i.Kill()
+	}
+
+	go func() {
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+
+		// Wait returns only once every process holding the output pipes is gone,
+		// so there is nothing left to kill after done is closed
+		select {
+		case <-i.done:
+			fmt.Printf("Instance %s exited after stop request\n", i.info.ID)
+		case <-timer.C:
+			fmt.Printf("Instance %s did not exit within %s, killing it\n", i.info.ID, grace)
+			_ = platform.KillProcessGroup(pid)
+		}
+	}()
+
+	return nil
+}
+
+// Kill terminates the whole process group immediately
+func (i *Instance) Kill() error {
+	i.markStopping()
+	if err := platform.KillProcessGroup(i.cmd.Process.Pid); err != nil {
+		return i.cmd.Process.Kill()
+	}
+	return nil
+}
+
+// markStopping flags the instance as stopping and reports whether it was not already
+func (i *Instance) markStopping() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	first := !i.stopping
+	i.stopping = true
+	return first
+}
@@ -0,0 +1,140 @@
+//go:build linux || darwin
+
+package instance
+
+import (
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+
+	"HyLauncher/internal/platform"
+)
+
+// start runs a shell script in its own process group and tracks it
+func start(t *testing.T, m *Manager, script string, info Info) *Instance {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", script)
+	platform.SetProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	inst := m.Track(cmd, info)
+	go inst.Wait()
+	t.Cleanup(func() {
+		_ = platform.KillProcessGroup(cmd.Process.Pid)
+		<-inst.Done()
+	})
+	return inst
+}
+
+// waitDone fails the test when an instance is still running after a few seconds
+func waitDone(t *testing.T, inst *Instance) {
+	t.Helper()
+	select {
+	case <-inst.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("instance %s did not exit", inst.Info().ID)
+	}
+}
+
+func TestManagerTracksInstances(t *testing.T) {
+	m := NewManager()
+
+	infos := []Info{
+		{Kind: KindGame, ProfileName: "a"},
+		{Kind: KindGame, ProfileName: "b"},
+		{Kind: KindServer, ProfileName: "server"},
+		{Kind: KindGame, ProfileName: "short"},
+	}
+	scripts := []string{"sleep 30", "sleep 30", "sleep 30", "sleep 0.2"}
+
+	// Start and list from several goroutines at once, -race checks the locking
+	insts := make([]*Instance, len(infos))
+	var wg sync.WaitGroup
+	for i := range infos {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			infos[i].StartedAt = time.Now().Add(time.Duration(i) * time.Second)
+			insts[i] = start(t, m, scripts[i], infos[i])
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = m.List()
+		}()
+	}
+	wg.Wait()
+
+	list := m.List()
+	if len(list) != len(infos) {
+		t.Fatalf("List() has %d instances, want %d", len(list), len(infos))
+	}
+	for i, info := range list {
+		if info.ProfileName != infos[i].ProfileName {
+			t.Errorf("List()[%d] = %s, want %s (oldest first)", i, info.ProfileName, infos[i].ProfileName)
+		}
+		if info.ID == "" || info.PID == 0 {
+			t.Errorf("List()[%d] has no ID or PID: %+v", i, info)
+		}
+	}
+	if servers := m.ListKind(KindServer); len(servers) != 1 || servers[0].ProfileName != "server" {
+		t.Errorf("ListKind(server) = %+v", servers)
+	}
+
+	// The short one exits on its own
+	waitDone(t, insts[3])
+	if insts[3].Stopping() {
+		t.Error("an instance that exited by itself is marked as stopping")
+	}
+
+	if err := m.Stop(insts[0].Info().ID, 5*time.Second); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := m.Kill(insts[2].Info().ID); err != nil {
+		t.Fatalf("Kill: %v", err)
+	}
+	waitDone(t, insts[0])
+	waitDone(t, insts[2])
+	if !insts[0].Stopping() || !insts[2].Stopping() {
+		t.Error("stopped instances are not marked as stopping")
+	}
+
+	if list := m.List(); len(list) != 1 || list[0].ID != insts[1].Info().ID {
+		t.Errorf("List() after stopping = %+v, want only %s", list, insts[1].Info().ID)
+	}
+	if _, ok := m.Get(insts[0].Info().ID); ok {
+		t.Error("a stopped instance is still tracked")
+	}
+	if err := m.Stop(insts[0].Info().ID, time.Second); err == nil {
+		t.Error("Stop of an exited instance succeeded")
+	}
+	if err := m.Kill("missing"); err == nil {
+		t.Error("Kill of an unknown instance succeeded")
+	}
+}
+
+func TestStopKillsAfterGrace(t *testing.T) {
+	m := NewManager()
+
+	// An ignored SIGTERM is inherited by sleep, so only the kill ends it
+	inst := start(t, m, `trap "" TERM; sleep 30`, Info{Kind: KindGame})
+	time.Sleep(100 * time.Millisecond)
+
+	if err := inst.Stop(200 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := inst.Stop(200 * time.Millisecond); err != nil {
+		t.Errorf("second Stop: %v", err)
+	}
+	select {
+	case <-inst.Done():
+		t.Fatal("the process exited before the grace period ended")
+	case <-time.After(100 * time.Millisecond):
+	}
+	waitDone(t, inst)
+
+	if state := inst.ProcessState(); state == nil || state.Success() {
+		t.Errorf("process state = %v, want killed", state)
+	}
+}
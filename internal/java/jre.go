@@ -29,10 +29,8 @@ type JREJSON struct {
 func DownloadJRE(ctx context.Context, reporter *progress.Reporter) error {
 	osName := env.GetOS()
 	arch := env.GetArch()
-	basePath := env.GetDefaultAppDir()
-
-	cacheDir := filepath.Join(basePath, "cache")
-	jreDir := filepath.Join(basePath, "release", "package", "jre")
+	cacheDir := env.GetCacheDir()
+	jreDir := env.GetJREDir()
 	latestDir := filepath.Join(jreDir, "latest")
 
 	if isJREInstalled(latestDir) {
@@ -136,7 +134,7 @@ func DownloadJRE(ctx context.Context, reporter *progress.Reporter) error {
 }
 
 func GetJavaExec() (string, error) {
-	jreDir := filepath.Join(env.GetDefaultAppDir(), "release", "package", "jre", "latest")
+	jreDir := filepath.Join(env.GetJREDir(), "latest")
 	javaBin := filepath.Join(jreDir, "bin", "java")
 	if runtime.GOOS == "windows" {
 		javaBin += ".exe"
@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrUnsupported is returned on platforms where processes cannot be sampled
+var ErrUnsupported = errors.New("process monitoring is not supported on this platform")
+
+// Sample is the resource usage of a process and its children at one point in time
+type Sample struct {
+	Time       time.Time `json:"time"`
+	CPUPercent float64   `json:"cpuPercent"` // 100 is one full core
+	RSS        uint64    `json:"rss"`        // bytes
+	Threads    int       `json:"threads"`
+	Processes  int       `json:"processes"`
+}
+
+// Stats is the latest sample along with the peak and average of the session
+type Stats struct {
+	InstanceID  string  `json:"instanceId"`
+	Current     Sample  `json:"current"`
+	PeakCPU     float64 `json:"peakCpu"`
+	PeakRSS     uint64  `json:"peakRss"`
+	PeakThreads int     `json:"peakThreads"`
+	AvgCPU      float64 `json:"avgCpu"`
+	AvgRSS      uint64  `json:"avgRss"`
+	Samples     int     `json:"samples"`
+}
+
+// Monitor samples a process tree until it is stopped
+type Monitor struct {
+	mu    sync.Mutex
+	stats Stats
+
+	cpuTotal float64
+	rssTotal float64
+}
+
+// Start samples pid and its children every interval until done is closed,
+// calling onStats after each sample
+func Start(instanceID string, pid int, interval time.Duration, done <-chan struct{}, onStats func(Stats)) (*Monitor, error) {
+	sampler, err := newSampler(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Monitor{stats: Stats{InstanceID: instanceID}}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				sample, err := sampler.sample()
+				if err != nil {
+					// The process is gone, Wait will close done shortly
+					continue
+				}
+				stats := m.add(sample)
+				if onStats != nil {
+					onStats(stats)
+				}
+			}
+		}
+	}()
+
+	return m, nil
+}
+
+// Stats returns the statistics collected so far
+func (m *Monitor) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
+
+func (m *Monitor) add(sample Sample) Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := &m.stats
+	s.Current = sample
+	s.Samples++
+
+	if sample.CPUPercent > s.PeakCPU {
+		s.PeakCPU = sample.CPUPercent
+	}
+	if sample.RSS > s.PeakRSS {
+		s.PeakRSS = sample.RSS
+	}
+	if sample.Threads > s.PeakThreads {
+		s.PeakThreads = sample.Threads
+	}
+
+	m.cpuTotal += sample.CPUPercent
+	m.rssTotal += float64(sample.RSS)
+	s.AvgCPU = m.cpuTotal / float64(s.Samples)
+	s.AvgRSS = uint64(m.rssTotal / float64(s.Samples))
+
+	return *s
+}
@@ -0,0 +1,34 @@
+package monitor
+
+import "testing"
+
+func TestMonitorAdd(t *testing.T) {
+	m := &Monitor{stats: Stats{InstanceID: "game"}}
+
+	samples := []Sample{
+		{CPUPercent: 50, RSS: 1000, Threads: 10},
+		{CPUPercent: 150, RSS: 3000, Threads: 30},
+		{CPUPercent: 100, RSS: 2000, Threads: 20},
+	}
+	var stats Stats
+	for _, s := range samples {
+		stats = m.add(s)
+	}
+
+	want := Stats{
+		InstanceID:  "game",
+		Current:     samples[2],
+		PeakCPU:     150,
+		PeakRSS:     3000,
+		PeakThreads: 30,
+		AvgCPU:      100,
+		AvgRSS:      2000,
+		Samples:     3,
+	}
+	if stats != want {
+		t.Errorf("add() = %+v, want %+v", stats, want)
+	}
+	if got := m.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
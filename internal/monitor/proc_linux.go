@@ -0,0 +1,153 @@
+//go:build linux
+
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// USER_HZ, the unit of the CPU times in /proc/<pid>/stat, is 100 on every Linux ABI
+const clockTicks = 100
+
+type procStat struct {
+	ppid    int
+	ticks   uint64 // utime + stime
+	threads int
+	rss     uint64 // pages
+}
+
+type sampler struct {
+	pid      int
+	pageSize uint64
+	last     time.Time
+	ticks    map[int]uint64
+}
+
+func newSampler(pid int) (*sampler, error) {
+	if _, err := readProcStat(pid); err != nil {
+		return nil, err
+	}
+	s := &sampler{
+		pid:      pid,
+		pageSize: uint64(os.Getpagesize()),
+		ticks:    make(map[int]uint64),
+	}
+
+	// Prime the CPU counters so the first real sample has a baseline
+	_, _ = s.sample()
+	return s, nil
+}
+
+// sample reads the process and all its descendants
+func (s *sampler) sample() (Sample, error) {
+	now := time.Now()
+
+	stats, err := s.tree()
+	if err != nil {
+		return Sample{}, err
+	}
+	return s.account(now, stats), nil
+}
+
+// account sums a snapshot of the process tree and turns the CPU ticks spent
+// since the previous snapshot into a percentage
+func (s *sampler) account(now time.Time, stats map[int]procStat) Sample {
+	sample := Sample{Time: now, Processes: len(stats)}
+	var delta uint64
+	ticks := make(map[int]uint64, len(stats))
+	for pid, st := range stats {
+		sample.Threads += st.threads
+		sample.RSS += st.rss * s.pageSize
+		ticks[pid] = st.ticks
+
+		// Processes that appeared since the last sample count from the next one
+		if prev, ok := s.ticks[pid]; ok && st.ticks >= prev {
+			delta += st.ticks - prev
+		}
+	}
+
+	if !s.last.IsZero() {
+		if elapsed := now.Sub(s.last).Seconds(); elapsed > 0 {
+			sample.CPUPercent = float64(delta) / clockTicks / elapsed * 100
+		}
+	}
+
+	s.last = now
+	s.ticks = ticks
+	return sample
+}
+
+// tree returns the stats of the root process and every descendant
+func (s *sampler) tree() (map[int]procStat, error) {
+	root, err := readProcStat(s.pid)
+	if err != nil {
+		return nil, err
+	}
+
+	all := map[int]procStat{s.pid: root}
+	children := make(map[int][]int)
+
+	entries, _ := os.ReadDir("/proc")
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == s.pid {
+			continue
+		}
+		st, err := readProcStat(pid)
+		if err != nil {
+			continue
+		}
+		all[pid] = st
+		children[st.ppid] = append(children[st.ppid], pid)
+	}
+
+	tree := make(map[int]procStat)
+	queue := []int{s.pid}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		tree[pid] = all[pid]
+		queue = append(queue, children[pid]...)
+	}
+	return tree, nil
+}
+
+func readProcStat(pid int) (procStat, error) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return procStat{}, err
+	}
+	return parseProcStat(pid, string(data))
+}
+
+// parseProcStat reads the fields we use from a /proc/<pid>/stat line
+func parseProcStat(pid int, data string) (procStat, error) {
+	// The command name may contain spaces and parentheses, fields start after the last ")"
+	end := strings.LastIndexByte(data, ')')
+	if end < 0 {
+		return procStat{}, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	fields := strings.Fields(data[end+1:])
+	if len(fields) < 22 {
+		return procStat{}, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+
+	// fields[0] is field 3 (state) in proc(5)
+	ppid, _ := strconv.Atoi(fields[1])
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	threads, _ := strconv.Atoi(fields[17])
+	rss, _ := strconv.ParseUint(fields[21], 10, 64)
+
+	return procStat{
+		ppid:    ppid,
+		ticks:   utime + stime,
+		threads: threads,
+		rss:     rss,
+	}, nil
+}
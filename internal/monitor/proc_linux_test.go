@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// A game thread named to confuse parsers that split on the first ")"
+const statLine = "4242 (Hytale) R 1 (Client x) S 4200 4242 4200 0 -1 4194560 1234 0 5 0 350 120 7 3 20 0 57 0 98765 8589934592 262144 18446744073709551615 1 1 0 0 0 0 0 4096 17663 0 0 0 17 3 0 0 0 0 0\n"
+
+func TestParseProcStat(t *testing.T) {
+	st, err := parseProcStat(4242, statLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := procStat{ppid: 4200, ticks: 350 + 120, threads: 57, rss: 262144}
+	if st != want {
+		t.Errorf("parseProcStat = %+v, want %+v", st, want)
+	}
+}
+
+func TestParseProcStatMalformed(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"4242 (Hytale S 4200 4242",
+		"4242 (Hytale) S 4200 4242 4200 0 -1",
+	} {
+		if _, err := parseProcStat(4242, line); err == nil {
+			t.Errorf("parseProcStat(%q) succeeded", line)
+		}
+	}
+}
+
+func TestReadProcStatSelf(t *testing.T) {
+	st, err := readProcStat(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.ppid != os.Getppid() || st.threads < 1 || st.rss == 0 {
+		t.Errorf("readProcStat(self) = %+v, parent %d", st, os.Getppid())
+	}
+}
+
+func TestSamplerAccount(t *testing.T) {
+	s := &sampler{pid: 10, pageSize: 4096, ticks: make(map[int]uint64)}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first := s.account(start, map[int]procStat{
+		10: {ticks: 1000, threads: 40, rss: 1000},
+		11: {ppid: 10, ticks: 50, threads: 2, rss: 24},
+	})
+	if first.CPUPercent != 0 {
+		t.Errorf("first sample CPU = %v, want 0 without a baseline", first.CPUPercent)
+	}
+	if first.RSS != 1024*4096 || first.Threads != 42 || first.Processes != 2 {
+		t.Errorf("first sample = %+v", first)
+	}
+
+	// 2 s later: the root spent 250 ticks, the child 50, a new child's 500
+	// ticks only count from the next sample
+	second := s.account(start.Add(2*time.Second), map[int]procStat{
+		10: {ticks: 1250, threads: 40, rss: 1000},
+		11: {ppid: 10, ticks: 100, threads: 2, rss: 24},
+		12: {ppid: 10, ticks: 500, threads: 1, rss: 100},
+	})
+	if want := 150.0; second.CPUPercent != want {
+		t.Errorf("second sample CPU = %v, want %v", second.CPUPercent, want)
+	}
+
+	// A restarted pid whose counter went backwards adds nothing
+	third := s.account(start.Add(3*time.Second), map[int]procStat{
+		10: {ticks: 1250},
+		11: {ticks: 10},
+		12: {ticks: 600},
+	})
+	if want := 100.0; third.CPUPercent != want {
+		t.Errorf("third sample CPU = %v, want %v", third.CPUPercent, want)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package monitor
+
+type sampler struct{}
+
+func newSampler(pid int) (*sampler, error) {
+	return nil, ErrUnsupported
+}
+
+func (s *sampler) sample() (Sample, error) {
+	return Sample{}, ErrUnsupported
+}
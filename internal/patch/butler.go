@@ -1,76 +1,174 @@
 package patch
 
 import (
+	"HyLauncher/internal/config"
 	"HyLauncher/internal/env"
+	"HyLauncher/internal/platform"
 	"HyLauncher/internal/progress"
 	"HyLauncher/pkg/download"
 	"HyLauncher/pkg/extract"
+	"HyLauncher/pkg/fileutil"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
+	"time"
 )
 
-func InstallButler(ctx context.Context, reporter *progress.Reporter) (string, error) {
-	toolsDir := filepath.Join(env.GetDefaultAppDir(), "tools", "butler")
-	zipPath := filepath.Join(toolsDir, "butler.zip")
-	tempZipPath := zipPath + ".tmp"
+const butlerStateFile = "butler.json"
 
-	if _, err := os.Stat(toolsDir); os.IsNotExist(err) {
-		os.MkdirAll(toolsDir, 0755)
-	}
+var (
+	butlerMu       sync.Mutex
+	butlerSettings = config.Default().Butler
+)
+
+// butlerState records what is installed in the tools dir
+type butlerState struct {
+	Version string `json:"version"` // pinned version, empty for imports
+	Source  string `json:"source"`  // download URL or imported file
+	SHA256  string `json:"sha256"`  // of the archive or imported binary
+}
 
-	var butlerPath string
+// SetButlerSettings sets the pinned version, checksum and local file used by InstallButler
+func SetButlerSettings(settings config.ButlerSettings) {
+	butlerMu.Lock()
+	butlerSettings = settings
+	butlerMu.Unlock()
+}
+
+// NativePatching reports whether the built-in patch applier runs before butler
+func NativePatching() bool {
+	butlerMu.Lock()
+	defer butlerMu.Unlock()
+	return butlerSettings.Native
+}
+
+// ButlerPath returns where the butler binary is installed
+func ButlerPath() string {
+	name := "butler"
 	if runtime.GOOS == "windows" {
-		butlerPath = filepath.Join(toolsDir, "butler.exe")
-	} else {
-		butlerPath = filepath.Join(toolsDir, "butler")
+		name += ".exe"
 	}
+	return filepath.Join(env.GetButlerDir(), name)
+}
 
-	// Remove any incomplete temp file from previous session
-	_ = os.Remove(tempZipPath)
+// ButlerVersion runs butler version and returns its first line
+func ButlerVersion() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	// If binary already exists, skip
-	if _, err := os.Stat(butlerPath); err == nil {
-		reporter.Report(progress.StageButler, 100, "Butler already installed")
-		return butlerPath, nil
+	cmd := exec.CommandContext(ctx, ButlerPath(), "version")
+	platform.HideConsoleWindow(cmd)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("butler version failed: %w", err)
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(line), nil
+}
+
+// butlerChannels are the broth channels butler is downloaded from. Other
+// platforms have to import a butler binary.
+var butlerChannels = map[string]bool{
+	"linux-amd64":   true,
+	"linux-arm64":   true,
+	"darwin-amd64":  true,
+	"darwin-arm64":  true,
+	"windows-amd64": true,
+}
+
+// butlerBaseURL is where the broth archives are downloaded from
+var butlerBaseURL = "https://broth.itch.zone/butler"
+
+// knownButlerSHA256 holds the SHA256 of the broth archive of a version, by
+// channel. Only checksums taken from the published archives belong here.
+// Without one the first download is trusted and its checksum recorded.
+var knownButlerSHA256 = map[string]map[string]string{}
+
+// butlerChannel returns the broth channel of this platform
+func butlerChannel() string {
+	return env.GetOS() + "-" + env.GetArch()
+}
+
+// butlerURL returns the broth archive of a butler version for a channel
+func butlerURL(channel, version string) (string, error) {
+	if !butlerChannels[channel] {
+		return "", fmt.Errorf("butler is not published for %s, import a butler binary instead", channel)
 	}
+	return fmt.Sprintf("%s/%s/%s/archive/default", butlerBaseURL, channel, version), nil
+}
 
-	// Determine download URL
-	var url string
-	switch runtime.GOOS {
-	case "windows":
-		url = "https://broth.itch.zone/butler/windows-amd64/LATEST/archive/default"
-	case "darwin":
-		url = "https://broth.itch.zone/butler/darwin-amd64/LATEST/archive/default"
-	case "linux":
-		url = "https://broth.itch.zone/butler/linux-amd64/LATEST/archive/default"
-	default:
-		return "", fmt.Errorf("unsupported OS")
+// expectedButlerSHA256 returns the checksum butler must match: the configured
+// one, else the known one for a download, else the one recorded when the same
+// source was installed before
+func expectedButlerSHA256(settings config.ButlerSettings, channel string, state butlerState, source string) string {
+	switch {
+	case settings.SHA256 != "":
+		return settings.SHA256
+	case settings.Path == "" && knownButlerSHA256[settings.Version][channel] != "":
+		return knownButlerSHA256[settings.Version][channel]
+	case state.Source == source:
+		return state.SHA256
 	}
+	return ""
+}
 
-	fmt.Println("Downloading Butler...")
-	reporter.Report(progress.StageButler, 0, "Downloading butler.zip...")
+// InstallButler installs the pinned butler version, or the local file set in
+// the settings, and replaces a binary that does not match. The archive or
+// file is checked against the configured SHA256, the one known for the
+// version or the one recorded by an earlier install of the same source. With
+// none of them it is installed and its checksum recorded.
+func InstallButler(ctx context.Context, reporter *progress.Reporter) (string, error) {
+	butlerMu.Lock()
+	settings := butlerSettings
+	butlerMu.Unlock()
 
-	// Create a scaler for the download portion (0-70%)
-	scaler := progress.NewScaler(reporter, progress.StageButler, 0, 70)
+	toolsDir := env.GetButlerDir()
+	butlerPath := ButlerPath()
+	_ = os.MkdirAll(toolsDir, 0755)
 
-	if err := download.DownloadWithReporter(tempZipPath, url, "butler.zip", reporter, progress.StageButler, scaler); err != nil {
-		_ = os.Remove(tempZipPath)
-		return "", err
+	channel := butlerChannel()
+	source := settings.Path
+	if source == "" {
+		if settings.Version == "" {
+			return "", fmt.Errorf("no butler version is pinned")
+		}
+		url, err := butlerURL(channel, settings.Version)
+		if err != nil {
+			return "", err
+		}
+		source = url
 	}
 
-	// Move temp file to final destination
-	if err := os.Rename(tempZipPath, zipPath); err != nil {
-		_ = os.Remove(tempZipPath)
-		return "", err
+	state := loadButlerState()
+	_, binErr := os.Stat(butlerPath)
+	if binErr == nil && state.Source == source && (settings.Path != "" || state.Version == settings.Version) {
+		reporter.Report(progress.StageButler, 100, "Butler already installed")
+		return butlerPath, nil
 	}
 
-	fmt.Println("Extracting Butler...")
-	reporter.Report(progress.StageButler, 80, "Extracting butler.zip")
+	expected := expectedButlerSHA256(settings, channel, state, source)
 
-	if err := extract.ExtractZip(zipPath, toolsDir); err != nil {
+	var sum string
+	var err error
+	if settings.Path != "" {
+		sum, err = importButler(settings.Path, expected, reporter)
+	} else {
+		sum, err = downloadButler(source, expected, reporter)
+	}
+	if err != nil {
+		// An older butler still works as a fallback while offline
+		if binErr == nil {
+			fmt.Printf("Warning: keeping the installed butler: %v\n", err)
+			reporter.Report(progress.StageButler, 100, "Butler update failed, keeping the installed version")
+			return butlerPath, nil
+		}
 		return "", err
 	}
 
@@ -81,10 +179,116 @@ func InstallButler(ctx context.Context, reporter *progress.Reporter) (string, er
 		}
 	}
 
-	// Cleanup zip
-	_ = os.Remove(zipPath)
+	version, err := ButlerVersion()
+	if err != nil {
+		// Drop it so the next install starts over
+		_ = os.Remove(butlerPath)
+		_ = os.Remove(filepath.Join(toolsDir, butlerStateFile))
+		return "", fmt.Errorf("installed butler does not run: %w", err)
+	}
+	fmt.Printf("Installed butler %s\n", version)
+
+	if expected == "" {
+		fmt.Printf("Warning: no butler checksum configured, recorded %s for %s\n", sum, source)
+	}
+
+	newState := butlerState{Source: source, SHA256: sum}
+	if settings.Path == "" {
+		newState.Version = settings.Version
+	}
+	if err := saveButlerState(newState); err != nil {
+		fmt.Printf("Warning: failed to save butler state: %v\n", err)
+	}
 
 	reporter.Report(progress.StageButler, 100, "Butler successfully installed!")
 
 	return butlerPath, nil
 }
+
+func downloadButler(url, expected string, reporter *progress.Reporter) (string, error) {
+	toolsDir := env.GetButlerDir()
+	zipPath := filepath.Join(toolsDir, "butler.zip")
+
+	// Remove any incomplete download from a previous session
+	_ = os.Remove(zipPath + ".tmp")
+	defer os.Remove(zipPath)
+
+	fmt.Println("Downloading Butler from", url)
+	reporter.Report(progress.StageButler, 0, "Downloading butler.zip...")
+
+	// Create a scaler for the download portion (0-70%)
+	scaler := progress.NewScaler(reporter, progress.StageButler, 0, 70)
+
+	if err := download.DownloadWithReporter(zipPath, url, "butler.zip", reporter, progress.StageButler, scaler); err != nil {
+		return "", err
+	}
+
+	return installButlerArchive(zipPath, expected, reporter)
+}
+
+// importButler installs a local butler binary or zip
+func importButler(path, expected string, reporter *progress.Reporter) (string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("butler file not found: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		return installButlerArchive(path, expected, reporter)
+	}
+
+	reporter.Report(progress.StageButler, 50, "Importing butler...")
+
+	sum, err := fileSHA256(path)
+	if err != nil {
+		return "", err
+	}
+	if expected != "" && !strings.EqualFold(sum, expected) {
+		return "", fmt.Errorf("butler SHA256 mismatch: expected %s got %s", expected, sum)
+	}
+
+	if err := fileutil.CopyFile(path, ButlerPath()); err != nil {
+		return "", fmt.Errorf("failed to import butler: %w", err)
+	}
+	return sum, nil
+}
+
+// installButlerArchive checks a butler zip and extracts it into the tools dir
+func installButlerArchive(zipPath, expected string, reporter *progress.Reporter) (string, error) {
+	reporter.Report(progress.StageButler, 75, "Verifying butler.zip")
+
+	sum, err := fileSHA256(zipPath)
+	if err != nil {
+		return "", err
+	}
+	if expected != "" && !strings.EqualFold(sum, expected) {
+		return "", fmt.Errorf("butler SHA256 mismatch: expected %s got %s", expected, sum)
+	}
+
+	fmt.Println("Extracting Butler...")
+	reporter.Report(progress.StageButler, 80, "Extracting butler.zip")
+
+	if err := extract.ExtractZip(zipPath, env.GetButlerDir()); err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(ButlerPath()); err != nil {
+		return "", fmt.Errorf("archive does not contain butler: %w", err)
+	}
+	return sum, nil
+}
+
+func loadButlerState() butlerState {
+	var state butlerState
+	data, err := os.ReadFile(filepath.Join(env.GetButlerDir(), butlerStateFile))
+	if err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+func saveButlerState(state butlerState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(env.GetButlerDir(), butlerStateFile), data, 0644)
+}
@@ -0,0 +1,211 @@
+package patch
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"HyLauncher/internal/config"
+	"HyLauncher/internal/env"
+)
+
+func TestButlerURL(t *testing.T) {
+	tests := []struct {
+		channel string
+		want    string // empty when the channel is refused
+	}{
+		{"linux-amd64", "https://broth.itch.zone/butler/linux-amd64/15.21.0/archive/default"},
+		{"darwin-amd64", "https://broth.itch.zone/butler/darwin-amd64/15.21.0/archive/default"},
+		{"windows-amd64", "https://broth.itch.zone/butler/windows-amd64/15.21.0/archive/default"},
+		{"darwin-arm64", "https://broth.itch.zone/butler/darwin-arm64/15.21.0/archive/default"},
+		{"linux-arm64", "https://broth.itch.zone/butler/linux-arm64/15.21.0/archive/default"},
+		{"windows-arm64", ""},
+		{"linux-unknown", ""},
+	}
+	for _, tt := range tests {
+		got, err := butlerURL(tt.channel, "15.21.0")
+		if tt.want == "" {
+			if err == nil || !strings.Contains(err.Error(), tt.channel) {
+				t.Errorf("butlerURL(%q) = %q, %v, want an error naming the channel", tt.channel, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("butlerURL(%q) = %q, %v, want %q", tt.channel, got, err, tt.want)
+		}
+	}
+}
+
+func TestKnownButlerSHA256(t *testing.T) {
+	hex := regexp.MustCompile(`^[0-9a-f]{64}$`)
+	for version, sums := range knownButlerSHA256 {
+		for channel, sum := range sums {
+			if !butlerChannels[channel] {
+				t.Errorf("%s has a checksum for %s, which is not downloaded", version, channel)
+			}
+			if !hex.MatchString(sum) {
+				t.Errorf("%s %s checksum %q is not a SHA256", version, channel, sum)
+			}
+		}
+	}
+}
+
+func TestExpectedButlerSHA256(t *testing.T) {
+	known := strings.Repeat("a", 64)
+	knownButlerSHA256["test"] = map[string]string{"linux-amd64": known}
+	t.Cleanup(func() { delete(knownButlerSHA256, "test") })
+
+	tests := []struct {
+		name     string
+		settings config.ButlerSettings
+		channel  string
+		state    butlerState
+		source   string
+		want     string
+	}{
+		{
+			name:     "configured wins",
+			settings: config.ButlerSettings{Version: "test", SHA256: "pinned"},
+			channel:  "linux-amd64",
+			want:     "pinned",
+		},
+		{
+			name:     "known for the version",
+			settings: config.ButlerSettings{Version: "test"},
+			channel:  "linux-amd64",
+			want:     known,
+		},
+		{
+			name:     "unknown channel",
+			settings: config.ButlerSettings{Version: "test"},
+			channel:  "windows-amd64",
+		},
+		{
+			name:     "known wins over the recorded hash",
+			settings: config.ButlerSettings{Version: "test"},
+			channel:  "linux-amd64",
+			state:    butlerState{Source: "url", SHA256: "recorded"},
+			source:   "url",
+			want:     known,
+		},
+		{
+			name:     "download checks the previous download",
+			settings: config.ButlerSettings{Version: "other"},
+			channel:  "linux-amd64",
+			state:    butlerState{Source: "url", SHA256: "recorded"},
+			source:   "url",
+			want:     "recorded",
+		},
+		{
+			name:     "download of another version",
+			settings: config.ButlerSettings{Version: "other"},
+			channel:  "linux-amd64",
+			state:    butlerState{Source: "old-url", SHA256: "recorded"},
+			source:   "url",
+		},
+		{
+			name:     "import checks the previous import",
+			settings: config.ButlerSettings{Path: "/tmp/butler"},
+			state:    butlerState{Source: "/tmp/butler", SHA256: "recorded"},
+			source:   "/tmp/butler",
+			want:     "recorded",
+		},
+		{
+			name:     "import of another file",
+			settings: config.ButlerSettings{Path: "/tmp/butler"},
+			state:    butlerState{Source: "/tmp/old", SHA256: "recorded"},
+			source:   "/tmp/butler",
+		},
+	}
+	for _, tt := range tests {
+		if got := expectedButlerSHA256(tt.settings, tt.channel, tt.state, tt.source); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// butlerZip returns a broth style archive holding a butler script
+func butlerZip(t *testing.T, version string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "butler", Method: zip.Deflate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(w, "#!/bin/sh\necho %s\n", version)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestInstallButlerDownload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test butler is a shell script")
+	}
+	if !butlerChannels[butlerChannel()] {
+		t.Skipf("butler is not downloaded for %s", butlerChannel())
+	}
+
+	archive := butlerZip(t, "v0.0.0-test")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive)
+	}))
+	t.Cleanup(srv.Close)
+	base := butlerBaseURL
+	butlerBaseURL = srv.URL
+	t.Cleanup(func() { butlerBaseURL = base })
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	SetButlerSettings(config.ButlerSettings{Version: "0.0.0-test"})
+	t.Cleanup(func() { SetButlerSettings(config.Default().Butler) })
+
+	// No known checksum: installed and recorded
+	if _, err := InstallButler(context.Background(), nil); err != nil {
+		t.Fatalf("first install: %v", err)
+	}
+	sum := sha256.Sum256(archive)
+	if state := loadButlerState(); state.SHA256 != hex.EncodeToString(sum[:]) || state.Version != "0.0.0-test" {
+		t.Fatalf("recorded state = %+v, want the archive checksum", state)
+	}
+	if version, err := ButlerVersion(); err != nil || version != "v0.0.0-test" {
+		t.Errorf("ButlerVersion() = %q, %v", version, err)
+	}
+
+	// A changed archive no longer matches the recorded checksum
+	if err := os.Remove(ButlerPath()); err != nil {
+		t.Fatal(err)
+	}
+	archive = butlerZip(t, "v0.0.0-changed")
+	_, err := InstallButler(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "SHA256 mismatch") {
+		t.Fatalf("install of a changed archive: error = %v, want a mismatch", err)
+	}
+	if _, err := os.Stat(ButlerPath()); !os.IsNotExist(err) {
+		t.Errorf("butler was installed from a changed archive: %v", err)
+	}
+
+	// A known checksum is enforced on a fresh install too
+	if err := os.Remove(filepath.Join(env.GetButlerDir(), butlerStateFile)); err != nil {
+		t.Fatal(err)
+	}
+	knownButlerSHA256["0.0.0-test"] = map[string]string{butlerChannel(): hex.EncodeToString(sum[:])}
+	t.Cleanup(func() { delete(knownButlerSHA256, "0.0.0-test") })
+	if _, err := InstallButler(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "SHA256 mismatch") {
+		t.Fatalf("install against a known checksum: error = %v, want a mismatch", err)
+	}
+}
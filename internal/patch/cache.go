@@ -0,0 +1,261 @@
+package patch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"HyLauncher/internal/env"
+)
+
+// DefaultCacheLimit is the patch cache size used until SetCacheLimit is called
+const DefaultCacheLimit int64 = 4096 << 20
+
+const cacheIndexFile = "index.json"
+
+var (
+	cacheMu    sync.Mutex
+	cacheLimit = DefaultCacheLimit
+)
+
+// CacheEntry is one cached patch, keyed by everything the patch depends on
+type CacheEntry struct {
+	Key      string    `json:"key"`
+	Channel  string    `json:"channel"`
+	OS       string    `json:"os"`
+	Arch     string    `json:"arch"`
+	From     int       `json:"from"`
+	To       int       `json:"to"`
+	Path     string    `json:"path"` // relative to the patch cache dir
+	Size     int64     `json:"size"`
+	SHA256   string    `json:"sha256"`
+	Added    time.Time `json:"added"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// CacheInfo describes the patch cache for the UI
+type CacheInfo struct {
+	Dir       string       `json:"dir"`
+	Entries   []CacheEntry `json:"entries"`
+	TotalSize int64        `json:"totalSize"`
+	Limit     int64        `json:"limit"`
+}
+
+type cacheIndex struct {
+	Entries map[string]*CacheEntry `json:"entries"`
+}
+
+// SetCacheLimit sets the patch cache size cap in bytes, 0 keeps no patches after an install
+func SetCacheLimit(limit int64) {
+	if limit < 0 {
+		limit = 0
+	}
+	cacheMu.Lock()
+	cacheLimit = limit
+	cacheMu.Unlock()
+}
+
+func newCacheEntry(channel string, from, to int) CacheEntry {
+	entry := CacheEntry{
+		Channel: channel,
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		From:    from,
+		To:      to,
+	}
+	entry.Key = fmt.Sprintf("%s/%s-%s/%d-%d", entry.Channel, entry.OS, entry.Arch, entry.From, entry.To)
+	entry.Path = filepath.FromSlash(entry.Key + ".pwr")
+	return entry
+}
+
+func loadCacheIndex() *cacheIndex {
+	index := &cacheIndex{Entries: make(map[string]*CacheEntry)}
+
+	data, err := os.ReadFile(filepath.Join(env.GetPatchCacheDir(), cacheIndexFile))
+	if err != nil {
+		return index
+	}
+	if err := json.Unmarshal(data, index); err != nil || index.Entries == nil {
+		fmt.Printf("Warning: patch cache index is unreadable, starting over: %v\n", err)
+		index.Entries = make(map[string]*CacheEntry)
+	}
+	return index
+}
+
+func saveCacheIndex(index *cacheIndex) error {
+	dir := env.GetPatchCacheDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, cacheIndexFile)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// cachedPatch returns the cached file for an entry if its size and hash still match
+func cachedPatch(entry CacheEntry) (string, bool) {
+	cacheMu.Lock()
+	index := loadCacheIndex()
+	cached, ok := index.Entries[entry.Key]
+	cacheMu.Unlock()
+
+	if !ok {
+		return "", false
+	}
+
+	path := filepath.Join(env.GetPatchCacheDir(), cached.Path)
+	valid := false
+	if info, err := os.Stat(path); err == nil && info.Size() == cached.Size {
+		sum, err := fileSHA256(path)
+		valid = err == nil && sum == cached.SHA256
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	index = loadCacheIndex()
+	if !valid {
+		fmt.Printf("Dropping damaged cached patch %s\n", entry.Key)
+		_ = os.Remove(path)
+		delete(index.Entries, entry.Key)
+		_ = saveCacheIndex(index)
+		return "", false
+	}
+
+	if cached, ok := index.Entries[entry.Key]; ok {
+		cached.LastUsed = time.Now()
+		_ = saveCacheIndex(index)
+	}
+	return path, true
+}
+
+// storeCachedPatch records a downloaded patch and evicts older ones over the cap
+func storeCachedPatch(entry CacheEntry) error {
+	path := filepath.Join(env.GetPatchCacheDir(), entry.Path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	sum, err := fileSHA256(path)
+	if err != nil {
+		return fmt.Errorf("failed to hash patch: %w", err)
+	}
+
+	now := time.Now()
+	entry.Size = info.Size()
+	entry.SHA256 = sum
+	entry.Added = now
+	entry.LastUsed = now
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	index := loadCacheIndex()
+	index.Entries[entry.Key] = &entry
+	evictCache(index, entry.Key)
+	return saveCacheIndex(index)
+}
+
+// evictCache removes the least recently used patches until the cache fits its
+// cap. keep is spared, it is about to be applied.
+func evictCache(index *cacheIndex, keep string) {
+	var total int64
+	entries := make([]*CacheEntry, 0, len(index.Entries))
+	for _, entry := range index.Entries {
+		total += entry.Size
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.Before(entries[j].LastUsed)
+	})
+
+	for _, entry := range entries {
+		if total <= cacheLimit {
+			break
+		}
+		if entry.Key == keep {
+			continue
+		}
+
+		fmt.Printf("Evicting cached patch %s (%d bytes)\n", entry.Key, entry.Size)
+		if err := os.Remove(filepath.Join(env.GetPatchCacheDir(), entry.Path)); err != nil && !os.IsNotExist(err) {
+			fmt.Printf("Warning: failed to remove cached patch: %v\n", err)
+			continue
+		}
+		delete(index.Entries, entry.Key)
+		total -= entry.Size
+	}
+}
+
+// TrimCache enforces the size cap, run after an install has applied its patches
+func TrimCache() error {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	index := loadCacheIndex()
+	evictCache(index, "")
+	return saveCacheIndex(index)
+}
+
+// GetCacheInfo lists the cached patches, most recently used first
+func GetCacheInfo() CacheInfo {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	info := CacheInfo{
+		Dir:     env.GetPatchCacheDir(),
+		Entries: []CacheEntry{},
+		Limit:   cacheLimit,
+	}
+
+	for _, entry := range loadCacheIndex().Entries {
+		info.Entries = append(info.Entries, *entry)
+		info.TotalSize += entry.Size
+	}
+
+	sort.Slice(info.Entries, func(i, j int) bool {
+		return info.Entries[i].LastUsed.After(info.Entries[j].LastUsed)
+	})
+
+	return info
+}
+
+// ClearCache removes every cached patch and the index
+func ClearCache() error {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	return os.RemoveAll(env.GetPatchCacheDir())
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
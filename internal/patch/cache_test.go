@@ -0,0 +1,187 @@
+package patch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"HyLauncher/internal/env"
+)
+
+// useTestCache points the patch cache at a temporary install with the given cap
+func useTestCache(t *testing.T, limit int64) {
+	t.Helper()
+	env.SetInstallDir(t.TempDir())
+	SetCacheLimit(limit)
+	t.Cleanup(func() {
+		env.SetInstallDir("")
+		SetCacheLimit(DefaultCacheLimit)
+	})
+}
+
+// writeCachedPatch writes a patch of size bytes where a download would leave it
+func writeCachedPatch(t *testing.T, entry CacheEntry, size int) {
+	t.Helper()
+	path := filepath.Join(env.GetPatchCacheDir(), entry.Path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Repeat("p", size)), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// cachedKeys lists the indexed keys, sorted
+func cachedKeys() []string {
+	keys := []string{}
+	for key := range loadCacheIndex().Entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestEvictCache(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		limit int64
+		sizes []int64 // entry i is version i to i+1, used i hours after base
+		spare int     // 1-based index of the entry being applied, 0 for none
+		want  []int
+	}{
+		{name: "under the cap", limit: 300, sizes: []int64{100, 100, 100}, want: []int{0, 1, 2}},
+		{name: "least recently used first", limit: 250, sizes: []int64{100, 100, 100}, want: []int{1, 2}},
+		{name: "until it fits", limit: 150, sizes: []int64{100, 100, 100}, want: []int{2}},
+		{name: "stops once it fits", limit: 150, sizes: []int64{100, 10, 100}, want: []int{1, 2}},
+		{name: "patch being applied is spared", limit: 150, sizes: []int64{100, 100, 100}, spare: 1, want: []int{0}},
+		{name: "spared patch alone over the cap", limit: 50, sizes: []int64{100, 100}, spare: 2, want: []int{1}},
+		{name: "zero cap empties", limit: 0, sizes: []int64{100, 100}, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestCache(t, tt.limit)
+
+			index := &cacheIndex{Entries: make(map[string]*CacheEntry)}
+			keys := make([]string, len(tt.sizes))
+			for i, size := range tt.sizes {
+				entry := newCacheEntry("release", i, i+1)
+				entry.Size = size
+				entry.LastUsed = base.Add(time.Duration(i) * time.Hour)
+				writeCachedPatch(t, entry, int(size))
+				index.Entries[entry.Key] = &entry
+				keys[i] = entry.Key
+			}
+
+			keep := ""
+			if tt.spare > 0 {
+				keep = keys[tt.spare-1]
+			}
+			evictCache(index, keep)
+
+			want := make([]string, 0, len(tt.want))
+			for _, i := range tt.want {
+				want = append(want, keys[i])
+			}
+			got := []string{}
+			for key := range index.Entries {
+				got = append(got, key)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("kept %q, want %q", got, want)
+			}
+
+			for i, key := range keys {
+				_, err := os.Stat(filepath.Join(env.GetPatchCacheDir(), filepath.FromSlash(key+".pwr")))
+				if _, kept := index.Entries[key]; kept != (err == nil) {
+					t.Errorf("patch %d indexed %v but file exists %v", i, kept, err == nil)
+				}
+			}
+		})
+	}
+}
+
+func TestCacheLRU(t *testing.T) {
+	useTestCache(t, 250)
+
+	first := newCacheEntry("release", 1, 2)
+	second := newCacheEntry("release", 2, 3)
+	third := newCacheEntry("release", 3, 4)
+
+	for _, entry := range []CacheEntry{first, second} {
+		writeCachedPatch(t, entry, 100)
+		if err := storeCachedPatch(entry); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Using the first patch again makes the second the least recently used
+	if _, ok := cachedPatch(first); !ok {
+		t.Fatal("first patch not found in the cache")
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	writeCachedPatch(t, third, 100)
+	if err := storeCachedPatch(third); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{first.Key, third.Key}
+	if got := cachedKeys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("cached %q, want %q", got, want)
+	}
+	if _, ok := cachedPatch(second); ok {
+		t.Error("evicted patch is still served")
+	}
+
+	info := GetCacheInfo()
+	if info.TotalSize != 200 || info.Limit != 250 || len(info.Entries) != 2 || info.Entries[0].Key != third.Key {
+		t.Errorf("cache info = %+v", info)
+	}
+
+	SetCacheLimit(0)
+	if err := TrimCache(); err != nil {
+		t.Fatal(err)
+	}
+	if got := cachedKeys(); len(got) != 0 {
+		t.Errorf("TrimCache with no cap kept %q", got)
+	}
+}
+
+func TestCachedPatchDamaged(t *testing.T) {
+	useTestCache(t, DefaultCacheLimit)
+
+	entry := newCacheEntry("beta", 0, 7)
+	writeCachedPatch(t, entry, 100)
+	if err := storeCachedPatch(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same size, different content
+	writeCachedPatch(t, entry, 99)
+	path := filepath.Join(env.GetPatchCacheDir(), entry.Path)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("x")
+	f.Close()
+
+	if _, ok := cachedPatch(entry); ok {
+		t.Fatal("damaged patch was served")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("damaged patch was not removed")
+	}
+	if got := cachedKeys(); len(got) != 0 {
+		t.Errorf("index still lists %q", got)
+	}
+}
@@ -0,0 +1,467 @@
+package patch
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"HyLauncher/pkg/fileutil"
+)
+
+// errInstallModified means the patch failed after the install dir was changed
+var errInstallModified = errors.New("patch applied partially")
+
+// nativeApplier applies a wharf patch without butler. New files are written to
+// the staging dir first, the install dir is only touched once the whole patch
+// decoded cleanly, so butler can still take over after a failure.
+type nativeApplier struct {
+	targetDir  string
+	stagingDir string
+	target     *wharfContainer
+	source     *wharfContainer
+	wire       *wireReader
+
+	unchanged map[int]bool // source files identical to the old file at the same path
+
+	openIndex int
+	openFile  *os.File
+
+	written    int64
+	onProgress func(done, total int64)
+}
+
+// ApplyNative applies pwrFile to targetDir without butler
+func ApplyNative(ctx context.Context, pwrFile, targetDir, stagingDir string, onProgress func(done, total int64)) error {
+	f, err := os.Open(pwrFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	raw := bufio.NewReaderSize(f, 1<<20)
+
+	var magic int32
+	if err := binary.Read(raw, binary.LittleEndian, &magic); err != nil {
+		return fmt.Errorf("failed to read patch magic: %w", err)
+	}
+	if magic != patchMagic {
+		return fmt.Errorf("not a wharf patch (magic %#x)", magic)
+	}
+
+	header, err := (&wireReader{r: raw}).next()
+	if err != nil {
+		return fmt.Errorf("failed to read patch header: %w", err)
+	}
+	algorithm, err := decodeCompression(header)
+	if err != nil {
+		return fmt.Errorf("failed to read patch header: %w", err)
+	}
+
+	dec, err := decompressor(algorithm, raw)
+	if err != nil {
+		return err
+	}
+	defer dec.Close()
+
+	a := &nativeApplier{
+		targetDir:  targetDir,
+		stagingDir: stagingDir,
+		wire:       &wireReader{r: bufio.NewReaderSize(dec, 1<<20)},
+		unchanged:  make(map[int]bool),
+		openIndex:  -1,
+		onProgress: onProgress,
+	}
+	defer a.closeOld()
+
+	if a.target, err = a.readContainer(); err != nil {
+		return fmt.Errorf("failed to read old container: %w", err)
+	}
+	if a.source, err = a.readContainer(); err != nil {
+		return fmt.Errorf("failed to read new container: %w", err)
+	}
+
+	for i := range a.source.Files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := a.applyFile(i); err != nil {
+			return fmt.Errorf("%s: %w", a.source.Files[i].Path, err)
+		}
+	}
+	a.closeOld()
+
+	if err := a.commit(); err != nil {
+		return fmt.Errorf("%w: %v", errInstallModified, err)
+	}
+	return nil
+}
+
+func (a *nativeApplier) readContainer() (*wharfContainer, error) {
+	b, err := a.wire.next()
+	if err != nil {
+		return nil, err
+	}
+	return decodeContainer(b)
+}
+
+func (a *nativeApplier) readOp() (syncOp, error) {
+	b, err := a.wire.next()
+	if err != nil {
+		return syncOp{}, err
+	}
+	return decodeSyncOp(b)
+}
+
+func (a *nativeApplier) stagedPath(i int) string {
+	return filepath.Join(a.stagingDir, filepath.FromSlash(a.source.Files[i].Path))
+}
+
+func (a *nativeApplier) applyFile(i int) error {
+	b, err := a.wire.next()
+	if err != nil {
+		return err
+	}
+	header, err := decodeSyncHeader(b)
+	if err != nil {
+		return err
+	}
+	if header.FileIndex != int64(i) {
+		return fmt.Errorf("%w: expected file %d, got %d", errCorruptPatch, i, header.FileIndex)
+	}
+
+	file := a.source.Files[i]
+
+	var first syncOp
+	if header.Type == syncRsync {
+		if first, err = a.readOp(); err != nil {
+			return err
+		}
+		// A single block range over the whole old file at the same path leaves it as is
+		if a.isWholeFileCopy(i, first) {
+			next, err := a.readOp()
+			if err != nil {
+				return err
+			}
+			if next.Type == opHeyYouDidIt {
+				a.unchanged[i] = true
+				a.progress(file.Size)
+				return nil
+			}
+			// Not alone after all, write both. first only holds numbers, the
+			// shared buffer now belongs to next.
+			return a.writeRsync(i, []syncOp{first, next})
+		}
+		return a.writeRsync(i, []syncOp{first})
+	}
+
+	if header.Type == syncBsdiff {
+		return a.writeBsdiff(i)
+	}
+	return fmt.Errorf("%w: unknown sync type %d", errCorruptPatch, header.Type)
+}
+
+func (a *nativeApplier) isWholeFileCopy(i int, op syncOp) bool {
+	if op.Type != opBlockRange || op.BlockIndex != 0 || op.FileIndex < 0 || op.FileIndex >= int64(len(a.target.Files)) {
+		return false
+	}
+	old := a.target.Files[op.FileIndex]
+	file := a.source.Files[i]
+	blocks := (old.Size + wharfBlockSize - 1) / wharfBlockSize
+	return old.Path == file.Path && old.Size == file.Size && op.BlockSpan == blocks && old.Size > 0
+}
+
+// createStaged opens the staging file of source file i
+func (a *nativeApplier) createStaged(i int) (*os.File, error) {
+	p := a.stagedPath(i)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+}
+
+func (a *nativeApplier) writeRsync(i int, pending []syncOp) error {
+	out, err := a.createStaged(i)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	w := bufio.NewWriterSize(out, 1<<20)
+
+	var written int64
+	for {
+		var op syncOp
+		if len(pending) > 0 {
+			op, pending = pending[0], pending[1:]
+		} else if op, err = a.readOp(); err != nil {
+			return err
+		}
+
+		switch op.Type {
+		case opHeyYouDidIt:
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			return a.checkSize(i, written)
+		case opData:
+			if _, err := w.Write(op.Data); err != nil {
+				return err
+			}
+			written += int64(len(op.Data))
+			a.progress(int64(len(op.Data)))
+		case opBlockRange:
+			n, err := a.copyBlocks(w, op)
+			if err != nil {
+				return err
+			}
+			written += n
+			a.progress(n)
+		default:
+			return fmt.Errorf("%w: unknown operation %d", errCorruptPatch, op.Type)
+		}
+	}
+}
+
+// copyBlocks copies a block range of an old file
+func (a *nativeApplier) copyBlocks(w io.Writer, op syncOp) (int64, error) {
+	old, size, err := a.old(op.FileIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	start := op.BlockIndex * wharfBlockSize
+	end := (op.BlockIndex + op.BlockSpan) * wharfBlockSize
+	if end > size {
+		end = size
+	}
+	if op.BlockIndex < 0 || op.BlockSpan <= 0 || start >= end {
+		return 0, fmt.Errorf("%w: block range %d+%d outside of old file", errCorruptPatch, op.BlockIndex, op.BlockSpan)
+	}
+
+	return io.Copy(w, io.NewSectionReader(old, start, end-start))
+}
+
+func (a *nativeApplier) writeBsdiff(i int) error {
+	b, err := a.wire.next()
+	if err != nil {
+		return err
+	}
+	targetIndex, err := decodeBsdiffHeader(b)
+	if err != nil {
+		return err
+	}
+	old, oldSize, err := a.old(targetIndex)
+	if err != nil {
+		return err
+	}
+
+	out, err := a.createStaged(i)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	w := bufio.NewWriterSize(out, 1<<20)
+
+	newSize := a.source.Files[i].Size
+	var oldPos, newPos int64
+	var buf []byte
+
+	for {
+		b, err := a.wire.next()
+		if err != nil {
+			return err
+		}
+		ctrl, err := decodeControl(b)
+		if err != nil {
+			return err
+		}
+		if ctrl.Eof {
+			break
+		}
+
+		if newPos+int64(len(ctrl.Add))+int64(len(ctrl.Copy)) > newSize {
+			return fmt.Errorf("%w: bsdiff output larger than the new file", errCorruptPatch)
+		}
+
+		// Add old bytes to the diff, bytes outside the old file count as zero
+		if cap(buf) < len(ctrl.Add) {
+			buf = make([]byte, len(ctrl.Add))
+		}
+		buf = buf[:len(ctrl.Add)]
+		for j := range buf {
+			buf[j] = 0
+		}
+		from, to := max(oldPos, 0), min(oldPos+int64(len(buf)), oldSize)
+		if from < to {
+			if _, err := old.ReadAt(buf[from-oldPos:to-oldPos], from); err != nil && err != io.EOF {
+				return err
+			}
+		}
+		for j := range buf {
+			buf[j] += ctrl.Add[j]
+		}
+
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+		if _, err := w.Write(ctrl.Copy); err != nil {
+			return err
+		}
+
+		n := int64(len(ctrl.Add) + len(ctrl.Copy))
+		newPos += n
+		oldPos += int64(len(ctrl.Add)) + ctrl.Seek
+		a.progress(n)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := a.checkSize(i, newPos); err != nil {
+		return err
+	}
+
+	op, err := a.readOp()
+	if err != nil {
+		return err
+	}
+	if op.Type != opHeyYouDidIt {
+		return fmt.Errorf("%w: expected end of file after bsdiff, got operation %d", errCorruptPatch, op.Type)
+	}
+	return nil
+}
+
+func (a *nativeApplier) checkSize(i int, written int64) error {
+	if want := a.source.Files[i].Size; written != want {
+		return fmt.Errorf("%w: wrote %d bytes, expected %d", errCorruptPatch, written, want)
+	}
+	return nil
+}
+
+// old returns an old file of the install, keeping the last one open
+func (a *nativeApplier) old(index int64) (*os.File, int64, error) {
+	if index < 0 || index >= int64(len(a.target.Files)) {
+		return nil, 0, fmt.Errorf("%w: old file %d does not exist", errCorruptPatch, index)
+	}
+	entry := a.target.Files[index]
+
+	if a.openIndex != int(index) {
+		a.closeOld()
+		f, err := os.Open(filepath.Join(a.targetDir, filepath.FromSlash(entry.Path)))
+		if err != nil {
+			return nil, 0, fmt.Errorf("old file missing, the install does not match the patch: %w", err)
+		}
+		a.openFile, a.openIndex = f, int(index)
+	}
+	return a.openFile, entry.Size, nil
+}
+
+func (a *nativeApplier) closeOld() {
+	if a.openFile != nil {
+		a.openFile.Close()
+		a.openFile, a.openIndex = nil, -1
+	}
+}
+
+func (a *nativeApplier) progress(n int64) {
+	a.written += n
+	if a.onProgress != nil {
+		a.onProgress(a.written, a.source.Size)
+	}
+}
+
+// commit moves the staged files in place and removes what the new build dropped
+func (a *nativeApplier) commit() error {
+	keepFiles := make(map[string]bool)
+	for _, e := range a.source.Files {
+		keepFiles[e.Path] = true
+	}
+	for _, e := range a.source.Symlinks {
+		keepFiles[e.Path] = true
+	}
+	keepDirs := make(map[string]bool)
+	for _, e := range a.source.Dirs {
+		keepDirs[e.Path] = true
+	}
+
+	for _, e := range append(append([]wharfEntry{}, a.target.Files...), a.target.Symlinks...) {
+		if !keepFiles[e.Path] {
+			if err := os.Remove(filepath.Join(a.targetDir, filepath.FromSlash(e.Path))); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	if err := os.MkdirAll(a.targetDir, 0755); err != nil {
+		return err
+	}
+	for _, e := range a.source.Dirs {
+		if err := os.MkdirAll(filepath.Join(a.targetDir, filepath.FromSlash(e.Path)), entryMode(e.Mode, 0755)); err != nil {
+			return err
+		}
+	}
+
+	for i, e := range a.source.Files {
+		dst := filepath.Join(a.targetDir, filepath.FromSlash(e.Path))
+		if !a.unchanged[i] {
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return err
+			}
+			// Whatever is in the way, a symlink or a directory, goes
+			if info, err := os.Lstat(dst); err == nil && !info.Mode().IsRegular() {
+				if err := os.RemoveAll(dst); err != nil {
+					return err
+				}
+			}
+			if err := fileutil.MoveFile(a.stagedPath(i), dst); err != nil {
+				return err
+			}
+		}
+		if runtime.GOOS != "windows" {
+			if err := os.Chmod(dst, entryMode(e.Mode, 0644)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, e := range a.source.Symlinks {
+		dst := filepath.Join(a.targetDir, filepath.FromSlash(e.Path))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(dst); err != nil {
+			return err
+		}
+		if err := os.Symlink(e.Dest, dst); err != nil {
+			return err
+		}
+	}
+
+	// Old directories go when the new build left them empty, deepest first
+	var dirs []string
+	for _, e := range a.target.Dirs {
+		if !keepDirs[e.Path] {
+			dirs = append(dirs, e.Path)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	for _, d := range dirs {
+		_ = os.Remove(filepath.Join(a.targetDir, filepath.FromSlash(d)))
+	}
+
+	return nil
+}
+
+// entryMode returns the permission bits of a container mode, or def when it has none
+func entryMode(mode uint32, def os.FileMode) os.FileMode {
+	if perm := os.FileMode(mode).Perm(); perm != 0 {
+		return perm
+	}
+	return def
+}
@@ -0,0 +1,533 @@
+package patch
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the .pwr fixtures in testdata")
+
+// blob returns n deterministic bytes from a small alphabet, so fixtures compress
+func blob(seed uint32, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		seed = seed*1664525 + 1013904223
+		b[i] = "ACGT"[seed>>30]
+	}
+	return string(b)
+}
+
+// patchFixture is an old and a new build, with the patch between them in testdata/<name>.pwr
+type patchFixture struct {
+	name     string
+	old, new tree
+	opts     encodeOptions
+	symlinks bool
+}
+
+func patchFixtures() []patchFixture {
+	big := blob(1, 2*wharfBlockSize+5000)
+	bigChanged := big[:wharfBlockSize] + blob(2, wharfBlockSize) + big[2*wharfBlockSize:]
+	moved := blob(3, wharfBlockSize+100)
+	jar := blob(4, 5000)
+	jarChanged := jar[:1000] + "patched" + jar[1007:] + blob(5, 300)
+	config := blob(6, 400)
+
+	return []patchFixture{
+		{
+			name: "rsync",
+			old: tree{
+				"Client/HytaleClient": {Data: "client build 1", Exec: true},
+				"Client/same.txt":     {Data: "unchanged"},
+				"Server/config.json":  {Data: `{"port":5520}`},
+				"Client/empty":        {Data: ""},
+			},
+			new: tree{
+				"Client/HytaleClient": {Data: "client build 2", Exec: true},
+				"Client/same.txt":     {Data: "unchanged"},
+				"Server/config.json":  {Data: `{"port":5521,"motd":"hi"}`},
+				"Client/empty":        {Data: ""},
+				"Client/added.txt":    {Data: "new file"},
+				"Server/start.sh":     {Data: "#!/bin/sh\n", Exec: true},
+			},
+			opts: encodeOptions{Compression: compressionNone},
+		},
+		{
+			name: "rsync-blocks",
+			old: tree{
+				"Assets/big.bin":   {Data: big},
+				"Assets/other.bin": {Data: moved},
+			},
+			new: tree{
+				"Assets/big.bin":         {Data: bigChanged},
+				"Assets/renamed/new.bin": {Data: moved},
+			},
+			opts: encodeOptions{Compression: compressionZstd},
+		},
+		{
+			name: "bsdiff",
+			old: tree{
+				"Server/HytaleServer.jar": {Data: jar},
+				"Server/config.json":      {Data: config},
+			},
+			new: tree{
+				"Server/HytaleServer.jar": {Data: jarChanged},
+				"Server/config.json":      {Data: config[:100]},
+			},
+			opts: encodeOptions{Compression: compressionBrotli, Bsdiff: map[string]bool{
+				"Server/HytaleServer.jar": true,
+				"Server/config.json":      true,
+			}},
+		},
+		{
+			name: "symlinks",
+			old: tree{
+				"lib/libfoo.so.1":   {Data: "foo 1"},
+				"lib/libfoo.so":     {Link: "libfoo.so.1"},
+				"lib/libbar.so":     {Link: "libbar.so.1"},
+				"lib/libbar.so.1":   {Data: "bar"},
+				"bin/tool":          {Data: "tool", Exec: true},
+				"bin/gone-link":     {Link: "../lib/libbar.so.1"},
+				"share/became-file": {Link: "elsewhere"},
+			},
+			new: tree{
+				"lib/libfoo.so.2":   {Data: "foo 2"},
+				"lib/libfoo.so":     {Link: "libfoo.so.2"},
+				"lib/libbar.so":     {Data: "bar, no longer a link"},
+				"lib/libbar.so.1":   {Data: "bar"},
+				"bin/tool":          {Link: "../lib/libfoo.so"},
+				"share/became-file": {Data: "was a link"},
+				"share/dangling":    {Link: "missing/target"},
+			},
+			opts:     encodeOptions{Compression: compressionGzip},
+			symlinks: true,
+		},
+		{
+			name: "removed",
+			old: tree{
+				"Client/HytaleClient":       {Data: "client", Exec: true},
+				"Client/removed.txt":        {Data: "gone"},
+				"Old/top.txt":               {Data: "gone"},
+				"Old/Nested/Deeper/a.txt":   {Data: "gone"},
+				"Old/Nested/b.txt":          {Data: "gone"},
+				"Kept/dropped.txt":          {Data: "gone"},
+				"Kept/stays.txt":            {Data: "kept"},
+				"WasEmpty":                  {Dir: true},
+				"Client/Data/removed.asset": {Data: "gone"},
+			},
+			new: tree{
+				"Client/HytaleClient": {Data: "client", Exec: true},
+				"Kept/stays.txt":      {Data: "kept"},
+				"NowEmpty":            {Dir: true},
+			},
+			opts: encodeOptions{Compression: compressionZstd},
+		},
+	}
+}
+
+// writeTree creates a tree under root
+func writeTree(t *testing.T, root string, tr tree) {
+	t.Helper()
+	for p, n := range tr {
+		dst := filepath.Join(root, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			t.Fatal(err)
+		}
+		var err error
+		switch {
+		case n.Dir:
+			err = os.MkdirAll(dst, 0755)
+		case n.Link != "":
+			err = os.Symlink(n.Link, dst)
+		default:
+			mode := os.FileMode(0644)
+			if n.Exec {
+				mode = 0755
+			}
+			err = os.WriteFile(dst, []byte(n.Data), mode)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readTree lists everything under root, directories included
+func readTree(t *testing.T, root string) tree {
+	t.Helper()
+	tr := tree{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == root {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			tr[rel] = node{Link: link}
+			return err
+		case d.IsDir():
+			tr[rel] = node{Dir: true}
+		default:
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			// The executable bit means nothing on Windows
+			exec := runtime.GOOS != "windows" && info.Mode().Perm()&0100 != 0
+			tr[rel] = node{Data: string(data), Exec: exec}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+// withDirs adds the directories a tree implies, as readTree reports them
+func withDirs(tr tree) tree {
+	out := tree{}
+	dirs, _, _ := container(tr)
+	for _, d := range dirs {
+		out[d] = node{Dir: true}
+	}
+	for p, n := range tr {
+		if runtime.GOOS == "windows" {
+			n.Exec = false
+		}
+		out[p] = n
+	}
+	return out
+}
+
+// diffTrees describes how got differs from want
+func diffTrees(got, want tree) string {
+	var diffs []string
+	for p, w := range want {
+		g, ok := got[p]
+		switch {
+		case !ok:
+			diffs = append(diffs, "missing "+p)
+		case g != w:
+			diffs = append(diffs, fmt.Sprintf("%s = %+.40v, want %+.40v", p, g, w))
+		}
+	}
+	for p := range got {
+		if _, ok := want[p]; !ok {
+			diffs = append(diffs, "extra "+p)
+		}
+	}
+	return strings.Join(diffs, "\n")
+}
+
+func skipWithoutSymlinks(t *testing.T, fx patchFixture) {
+	if fx.symlinks && runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on Windows")
+	}
+}
+
+func fixturePath(name string) string {
+	return filepath.Join("testdata", name+".pwr")
+}
+
+// applyToCopy writes old into a fresh install with an untracked file and applies patch
+func applyToCopy(t *testing.T, old tree, pwr string) (string, error) {
+	t.Helper()
+	root := t.TempDir()
+	install := filepath.Join(root, "install")
+	writeTree(t, install, old)
+	writeTree(t, install, tree{"UserData/settings.json": {Data: "{}"}})
+	return install, ApplyNative(context.Background(), pwr, install, filepath.Join(root, "staging"), nil)
+}
+
+// TestNativeFixtures applies the patches in testdata. Run with -update to
+// rewrite them from the trees above. They come from encodePatch, not butler,
+// so only TestNativeMatchesButler checks the applier against real patches.
+func TestNativeFixtures(t *testing.T) {
+	for _, fx := range patchFixtures() {
+		t.Run(fx.name, func(t *testing.T) {
+			skipWithoutSymlinks(t, fx)
+
+			if *update {
+				if err := os.WriteFile(fixturePath(fx.name), encodePatch(fx.old, fx.new, fx.opts), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			install, err := applyToCopy(t, fx.old, fixturePath(fx.name))
+			if err != nil {
+				t.Fatalf("ApplyNative: %v", err)
+			}
+
+			want := withDirs(fx.new)
+			want["UserData"] = node{Dir: true}
+			want["UserData/settings.json"] = node{Data: "{}"}
+			if diff := diffTrees(readTree(t, install), want); diff != "" {
+				t.Errorf("install after the patch:\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestNativeCompressions applies every fixture with every compression
+func TestNativeCompressions(t *testing.T) {
+	compressions := map[string]int64{
+		"none":   compressionNone,
+		"brotli": compressionBrotli,
+		"gzip":   compressionGzip,
+		"zstd":   compressionZstd,
+	}
+	for _, fx := range patchFixtures() {
+		for name, compression := range compressions {
+			t.Run(fx.name+"/"+name, func(t *testing.T) {
+				skipWithoutSymlinks(t, fx)
+
+				opts := fx.opts
+				opts.Compression = compression
+				pwr := filepath.Join(t.TempDir(), "patch.pwr")
+				if err := os.WriteFile(pwr, encodePatch(fx.old, fx.new, opts), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				install, err := applyToCopy(t, fx.old, pwr)
+				if err != nil {
+					t.Fatalf("ApplyNative: %v", err)
+				}
+				got := readTree(t, install)
+				delete(got, "UserData")
+				delete(got, "UserData/settings.json")
+				if diff := diffTrees(got, withDirs(fx.new)); diff != "" {
+					t.Errorf("install after the patch:\n%s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestNativeProgress(t *testing.T) {
+	fx := patchFixtures()[1]
+	root := t.TempDir()
+	writeTree(t, filepath.Join(root, "install"), fx.old)
+
+	var last, total int64
+	err := ApplyNative(context.Background(), fixturePath(fx.name), filepath.Join(root, "install"), filepath.Join(root, "staging"), func(done, size int64) {
+		if done < last {
+			t.Errorf("progress went back from %d to %d", last, done)
+		}
+		last, total = done, size
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(len(fx.new["Assets/big.bin"].Data) + len(fx.new["Assets/renamed/new.bin"].Data)); last != want || total != want {
+		t.Errorf("progress ended at %d of %d, want %d", last, total, want)
+	}
+}
+
+func TestNativeCorrupt(t *testing.T) {
+	old := tree{"a.txt": {Data: "old a"}, "b.txt": {Data: "old b"}}
+	newTree := tree{"a.txt": {Data: "new a"}, "b.txt": {Data: "old b"}}
+	valid := encodePatch(old, newTree, encodeOptions{Compression: compressionZstd})
+
+	oldC, newC := encodeContainer(old), encodeContainer(newTree)
+	rsync := func(file int) []byte { return protoBuf(nil).varint(1, syncRsync).varint(16, uint64(file)) }
+	data := func(s string) []byte { return protoBuf(nil).varint(1, opData).bytes(5, []byte(s)) }
+	blocks := func(file, block, span int) []byte {
+		return protoBuf(nil).varint(1, opBlockRange).varint(2, uint64(file)).varint(3, uint64(block)).varint(4, uint64(span))
+	}
+	done := protoBuf(nil).varint(1, opHeyYouDidIt)
+
+	badMagic := append([]byte{}, valid...)
+	badMagic[0] ^= 0xff
+
+	tests := map[string][]byte{
+		"empty file":          {},
+		"bad magic":           badMagic,
+		"header only":         valid[:4],
+		"truncated":           valid[:len(valid)/2],
+		"last byte missing":   valid[:len(valid)-1],
+		"unknown compression": framePatch(9, wireMessages(oldC, newC)),
+		"garbage body":        framePatch(compressionNone, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}),
+		"file out of order":   framePatch(compressionNone, wireMessages(oldC, newC, rsync(1), data("new a"), done)),
+		"too short":           framePatch(compressionNone, wireMessages(oldC, newC, rsync(0), data("new"), done)),
+		"too long":            framePatch(compressionNone, wireMessages(oldC, newC, rsync(0), data("new a!"), done)),
+		"unknown operation":   framePatch(compressionNone, wireMessages(oldC, newC, rsync(0), protoBuf(nil).varint(1, 7), done)),
+		"unknown sync type":   framePatch(compressionNone, wireMessages(oldC, newC, protoBuf(nil).varint(1, 5), done)),
+		"old file index":      framePatch(compressionNone, wireMessages(oldC, newC, rsync(0), blocks(5, 0, 1), done)),
+		"block outside file":  framePatch(compressionNone, wireMessages(oldC, newC, rsync(0), blocks(0, 3, 1), done)),
+		"empty block span":    framePatch(compressionNone, wireMessages(oldC, newC, rsync(0), blocks(0, 0, 0), done)),
+		"missing end of file": framePatch(compressionNone, wireMessages(oldC, newC, rsync(0), data("new a"))),
+		"bsdiff too large": framePatch(compressionNone, wireMessages(oldC, newC,
+			protoBuf(nil).varint(1, syncBsdiff).varint(16, 0), protoBuf(nil).varint(1, 0),
+			protoBuf(nil).bytes(2, []byte("far too long for the file")))),
+		"bsdiff without end": framePatch(compressionNone, wireMessages(oldC, newC,
+			protoBuf(nil).varint(1, syncBsdiff).varint(16, 0), protoBuf(nil).varint(1, 0),
+			protoBuf(nil).bytes(2, []byte("new a")), protoBuf(nil).varint(4, 1), data("x"))),
+	}
+
+	for name, pwrData := range tests {
+		t.Run(name, func(t *testing.T) {
+			pwr := filepath.Join(t.TempDir(), "patch.pwr")
+			if err := os.WriteFile(pwr, pwrData, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			install, err := applyToCopy(t, old, pwr)
+			if err == nil {
+				t.Fatal("corrupt patch applied")
+			}
+			// Butler can still take over, nothing was touched
+			if errors.Is(err, errInstallModified) {
+				t.Errorf("error = %v, the install was modified", err)
+			}
+			got := readTree(t, install)
+			delete(got, "UserData")
+			delete(got, "UserData/settings.json")
+			if diff := diffTrees(got, withDirs(old)); diff != "" {
+				t.Errorf("install changed:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNativePathTraversal(t *testing.T) {
+	for _, p := range []string{"../escape.txt", "sub/../../escape.txt", "/tmp/escape.txt", `..\escape.txt`} {
+		t.Run(p, func(t *testing.T) {
+			evil := protoBuf(nil).bytes(2, protoBuf(nil).bytes(1, []byte(p)).varint(2, 0644).varint(3, 4)).varint(16, 4)
+			body := wireMessages(encodeContainer(tree{}), evil,
+				protoBuf(nil).varint(1, syncRsync).varint(16, 0),
+				protoBuf(nil).varint(1, opData).bytes(5, []byte("evil")),
+				protoBuf(nil).varint(1, opHeyYouDidIt))
+
+			root := t.TempDir()
+			pwr := filepath.Join(root, "patch.pwr")
+			if err := os.WriteFile(pwr, framePatch(compressionNone, body), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			install := filepath.Join(root, "game", "install")
+			err := ApplyNative(context.Background(), pwr, install, filepath.Join(root, "game", "staging"), nil)
+			if !errors.Is(err, errCorruptPatch) {
+				t.Errorf("error = %v, want errCorruptPatch", err)
+			}
+			for _, dir := range []string{root, filepath.Join(root, "game")} {
+				if _, err := os.Stat(filepath.Join(dir, "escape.txt")); err == nil {
+					t.Errorf("patch wrote %s", filepath.Join(dir, "escape.txt"))
+				}
+			}
+		})
+	}
+}
+
+func TestNativeInstallMismatch(t *testing.T) {
+	fx := patchFixtures()[1]
+	root := t.TempDir()
+	install := filepath.Join(root, "install")
+	writeTree(t, install, tree{"Assets/big.bin": fx.old["Assets/big.bin"]})
+
+	err := ApplyNative(context.Background(), fixturePath(fx.name), install, filepath.Join(root, "staging"), nil)
+	if err == nil || errors.Is(err, errInstallModified) {
+		t.Fatalf("error = %v, want a failure before touching the install", err)
+	}
+	if got := readTree(t, install)["Assets/big.bin"]; got != fx.old["Assets/big.bin"] {
+		t.Error("install changed")
+	}
+}
+
+func TestNativeCancelled(t *testing.T) {
+	fx := patchFixtures()[0]
+	root := t.TempDir()
+	writeTree(t, filepath.Join(root, "install"), fx.old)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := ApplyNative(ctx, fixturePath(fx.name), filepath.Join(root, "install"), filepath.Join(root, "staging"), nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+// testButler returns a butler binary to compare against, set HYLAUNCHER_TEST_BUTLER
+// or put butler on the PATH
+func testButler(t *testing.T) string {
+	t.Helper()
+	if p := os.Getenv("HYLAUNCHER_TEST_BUTLER"); p != "" {
+		return p
+	}
+	p, err := exec.LookPath("butler")
+	if err != nil {
+		t.Skip("butler not found, set HYLAUNCHER_TEST_BUTLER to compare against it")
+	}
+	return p
+}
+
+func runButler(t *testing.T, butler string, args ...string) {
+	t.Helper()
+	out, err := exec.Command(butler, args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("butler %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+// TestNativeMatchesButler applies the same patches with butler and natively
+// and compares the results byte for byte. Patches made by butler diff from
+// the fixture trees are applied natively as well.
+func TestNativeMatchesButler(t *testing.T) {
+	butler := testButler(t)
+
+	for _, fx := range patchFixtures() {
+		t.Run(fx.name, func(t *testing.T) {
+			skipWithoutSymlinks(t, fx)
+			root := t.TempDir()
+
+			byButler := filepath.Join(root, "butler")
+			writeTree(t, byButler, fx.old)
+			runButler(t, butler, "apply", "--staging-dir", filepath.Join(root, "butler-staging"), fixturePath(fx.name), byButler)
+
+			native, err := applyToCopy(t, fx.old, fixturePath(fx.name))
+			if err != nil {
+				t.Fatalf("ApplyNative: %v", err)
+			}
+			got := readTree(t, native)
+			delete(got, "UserData")
+			delete(got, "UserData/settings.json")
+			if diff := diffTrees(got, readTree(t, byButler)); diff != "" {
+				t.Errorf("native and butler apply differ:\n%s", diff)
+			}
+
+			// A patch written by butler diff itself
+			oldDir, newDir := filepath.Join(root, "old"), filepath.Join(root, "new")
+			writeTree(t, oldDir, fx.old)
+			writeTree(t, newDir, fx.new)
+			pwr := filepath.Join(root, "butler.pwr")
+			runButler(t, butler, "diff", oldDir, newDir, pwr)
+
+			native, err = applyToCopy(t, fx.old, pwr)
+			if err != nil {
+				t.Fatalf("ApplyNative on a butler diff patch: %v", err)
+			}
+			got = readTree(t, native)
+			delete(got, "UserData")
+			delete(got, "UserData/settings.json")
+			if diff := diffTrees(got, readTree(t, newDir)); diff != "" {
+				t.Errorf("native apply of a butler diff patch:\n%s", diff)
+			}
+		})
+	}
+}
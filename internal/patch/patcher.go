@@ -2,6 +2,7 @@ package patch
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -21,8 +22,17 @@ func ApplyPWR(ctx context.Context, pwrFile string, reporter *progress.Reporter)
 
 // ApplyPWRWithOptions - New function with additional options
 func ApplyPWRWithOptions(ctx context.Context, channel string, pwrFile string, installDirName string, reporter *progress.Reporter) error {
-	gameInstallDir := filepath.Join(env.GetDefaultAppDir(), channel, "package", "game", installDirName)
-	stagingDir := filepath.Join(env.GetDefaultAppDir(), channel, "package", "game", "staging-temp")
+	return ApplyPatch(ctx, channel, pwrFile, installDirName, progress.NewScaler(reporter, progress.StagePatch, 0, 100))
+}
+
+// ApplyPatch applies one .pwr file, reporting into the scaler's range
+func ApplyPatch(ctx context.Context, channel string, pwrFile string, installDirName string, scaler *progress.Scaler) error {
+	return ApplyPatchTo(ctx, channel, pwrFile, env.GetGameDir(channel, installDirName), scaler)
+}
+
+// ApplyPatchTo applies one .pwr file to any directory
+func ApplyPatchTo(ctx context.Context, channel string, pwrFile string, gameInstallDir string, scaler *progress.Scaler) error {
+	stagingDir := env.GetGameDir(channel, "staging-temp")
 
 	// Create parent directory
 	_ = os.MkdirAll(filepath.Dir(gameInstallDir), 0755)
@@ -36,9 +46,37 @@ func ApplyPWRWithOptions(ctx context.Context, channel string, pwrFile string, in
 	_ = os.RemoveAll(stagingDir)
 	_ = os.MkdirAll(stagingDir, 0755)
 
-	butlerPath := filepath.Join(env.GetDefaultAppDir(), "tools", "butler", "butler")
-	if runtime.GOOS == "windows" {
-		butlerPath += ".exe"
+	butlerPath := ButlerPath()
+
+	// The built-in applier is opt-in. Butler only runs after it when it gave
+	// up before touching the install.
+	if NativePatching() {
+		scaler.Report(progress.StagePatch, 0, "Applying game patch...")
+		lastPercent := -1
+		err := ApplyNative(ctx, pwrFile, gameInstallDir, stagingDir, func(done, total int64) {
+			if total <= 0 {
+				return
+			}
+			if percent := int(done * 100 / total); percent != lastPercent {
+				lastPercent = percent
+				scaler.Report(progress.StagePatch, float64(percent), "Applying game patch...")
+			}
+		})
+		if err == nil {
+			_ = os.RemoveAll(stagingDir)
+			scaler.Report(progress.StagePatch, 100, "Game patched!")
+			return nil
+		}
+		if errors.Is(err, errInstallModified) || ctx.Err() != nil {
+			return fmt.Errorf("failed to apply patch: %w", err)
+		}
+		if _, statErr := os.Stat(butlerPath); statErr != nil {
+			return fmt.Errorf("failed to apply patch and butler is not installed: %w", err)
+		}
+		fmt.Printf("Built-in patch applier failed, falling back to butler: %v\n", err)
+
+		_ = os.RemoveAll(stagingDir)
+		_ = os.MkdirAll(stagingDir, 0755)
 	}
 
 	// Ensure butler is executable on non-Windows
@@ -71,7 +109,7 @@ func ApplyPWRWithOptions(ctx context.Context, channel string, pwrFile string, in
 		cmd.Stderr = os.Stderr
 	}
 
-	reporter.Report(progress.StagePatch, 60, "Applying game patch...")
+	scaler.Report(progress.StagePatch, 60, "Applying game patch...")
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("butler apply failed (check logs/butler_apply.log): %w", err)
@@ -79,37 +117,42 @@ func ApplyPWRWithOptions(ctx context.Context, channel string, pwrFile string, in
 
 	_ = os.RemoveAll(stagingDir)
 
-	reporter.Report(progress.StagePatch, 100, "Game patched!")
+	scaler.Report(progress.StagePatch, 100, "Game patched!")
 
 	return nil
 }
 
 func DownloadPWR(ctx context.Context, versionType string, prevVer int, targetVer int, reporter *progress.Reporter) (string, error) {
-	cacheDir := filepath.Join(env.GetDefaultAppDir(), "cache")
-	_ = os.MkdirAll(cacheDir, 0755)
-	osName := runtime.GOOS
-	arch := runtime.GOARCH
-	fileName := fmt.Sprintf("%d.pwr", targetVer)
-	dest := filepath.Join(cacheDir, fileName)
-	tempDest := dest + ".tmp"
+	step := PatchStep{From: prevVer, To: targetVer, URL: PatchURL(versionType, prevVer, targetVer)}
+	return DownloadPatch(ctx, versionType, step, progress.NewScaler(reporter, progress.StagePWR, 0, 100))
+}
 
-	_ = os.Remove(tempDest)
+// DownloadPatch returns one step of an update plan from the patch cache,
+// downloading it first when it is missing. Progress goes to the scaler's range.
+func DownloadPatch(ctx context.Context, channel string, step PatchStep, scaler *progress.Scaler) (string, error) {
+	entry := newCacheEntry(channel, step.From, step.To)
 
-	if _, err := os.Stat(dest); err == nil {
-		reporter.Report(progress.StagePWR, 100, "PWR file cached")
-		return dest, nil
+	if path, ok := cachedPatch(entry); ok {
+		scaler.Report(progress.StagePWR, 100, "PWR file cached")
+		return path, nil
 	}
 
-	// Create a scaler for the download portion (0-100%)
-	scaler := progress.NewScaler(reporter, progress.StagePWR, 0, 100)
+	dest := filepath.Join(env.GetPatchCacheDir(), entry.Path)
+	tempDest := dest + ".tmp"
+	fileName := filepath.Base(dest)
+
+	_ = os.Remove(tempDest)
 
-	url := fmt.Sprintf("https://game-patches.hytale.com/patches/%s/%s/%s/%d/%s", osName, arch, versionType, prevVer, fileName)
-	if err := download.DownloadWithReporter(dest, url, fileName, reporter, progress.StagePWR, scaler); err != nil {
+	if err := download.DownloadWithReporter(dest, step.URL, fileName, nil, progress.StagePWR, scaler); err != nil {
 		_ = os.Remove(tempDest)
 		return "", err
 	}
 
-	reporter.Report(progress.StagePWR, 100, "PWR file downloaded")
+	if err := storeCachedPatch(entry); err != nil {
+		fmt.Printf("Warning: failed to index cached patch: %v\n", err)
+	}
+
+	scaler.Report(progress.StagePWR, 100, "PWR file downloaded")
 
 	return dest, nil
 }
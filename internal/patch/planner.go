@@ -0,0 +1,193 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// patchBaseURL is where patches are published, tests point it at a fake server
+var patchBaseURL = "https://game-patches.hytale.com/patches"
+
+// Longer chains cost too many requests to probe
+const maxChainSteps = 10
+
+// Route kinds
+const (
+	RouteFull   = "full"   // full build from version 0
+	RouteDirect = "direct" // one patch from the local version
+	RouteChain  = "chain"  // one patch per version in between
+)
+
+// PatchStep is a single .pwr download that takes From to To
+type PatchStep struct {
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	URL  string `json:"url"`
+	Size int64  `json:"size"` // -1 when the server sends no Content-Length
+}
+
+// UpdatePlan is the route chosen to bring an install from one version to another
+type UpdatePlan struct {
+	Kind      string      `json:"kind"`
+	From      int         `json:"from"`
+	To        int         `json:"to"`
+	Steps     []PatchStep `json:"steps"`
+	TotalSize int64       `json:"totalSize"` // -1 when any step size is unknown
+}
+
+// PatchURL returns the patch that updates from one version to another, 0 is a full build
+func PatchURL(channel string, from, to int) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%d/%d.pwr", patchBaseURL, runtime.GOOS, runtime.GOARCH, channel, from, to)
+}
+
+// PlanUpdate compares a full build, a direct patch and a chain of single-step
+// patches and picks the smallest route the server actually has
+func PlanUpdate(ctx context.Context, channel string, from, to int) (*UpdatePlan, error) {
+	if to <= 0 {
+		return nil, fmt.Errorf("invalid target version %d", to)
+	}
+	if from >= to {
+		from = 0
+	}
+
+	routes := [][]PatchStep{{{From: 0, To: to}}}
+	if from > 0 {
+		routes = append(routes, []PatchStep{{From: from, To: to}})
+		if to-from > 1 && to-from <= maxChainSteps {
+			chain := make([]PatchStep, 0, to-from)
+			for v := from; v < to; v++ {
+				chain = append(chain, PatchStep{From: v, To: v + 1})
+			}
+			routes = append(routes, chain)
+		}
+	}
+
+	sizes := probeSizes(ctx, channel, routes)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var plans []*UpdatePlan
+	for _, steps := range routes {
+		plan := &UpdatePlan{Kind: routeKind(steps), From: steps[0].From, To: to}
+		available := true
+		for _, step := range steps {
+			size, ok := sizes[[2]int{step.From, step.To}]
+			if !ok {
+				available = false
+				break
+			}
+			step.URL = PatchURL(channel, step.From, step.To)
+			step.Size = size
+			plan.Steps = append(plan.Steps, step)
+			if size < 0 || plan.TotalSize < 0 {
+				plan.TotalSize = -1
+			} else {
+				plan.TotalSize += size
+			}
+		}
+		if available {
+			plans = append(plans, plan)
+		}
+	}
+
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("no patch route to version %d found for %s/%s", to, runtime.GOOS, runtime.GOARCH)
+	}
+
+	// Smallest download first, routes of unknown size after the known ones,
+	// fewer steps breaks ties
+	sort.SliceStable(plans, func(i, j int) bool {
+		a, b := plans[i], plans[j]
+		if (a.TotalSize < 0) != (b.TotalSize < 0) {
+			return a.TotalSize >= 0
+		}
+		if a.TotalSize != b.TotalSize {
+			return a.TotalSize < b.TotalSize
+		}
+		return len(a.Steps) < len(b.Steps)
+	})
+
+	for _, plan := range plans {
+		fmt.Printf("Update route %s from %d: %d steps, %d bytes\n", plan.Kind, plan.From, len(plan.Steps), plan.TotalSize)
+	}
+
+	return plans[0], nil
+}
+
+func routeKind(steps []PatchStep) string {
+	switch {
+	case steps[0].From == 0:
+		return RouteFull
+	case len(steps) == 1:
+		return RouteDirect
+	default:
+		return RouteChain
+	}
+}
+
+// probeSizes sends a HEAD request for every step of every route and returns
+// the sizes of the patches that exist
+func probeSizes(ctx context.Context, channel string, routes [][]PatchStep) map[[2]int]int64 {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sizes   = make(map[[2]int]int64)
+		seen    = make(map[[2]int]bool)
+		limiter = make(chan struct{}, 4)
+	)
+
+	for _, steps := range routes {
+		for _, step := range steps {
+			key := [2]int{step.From, step.To}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			wg.Add(1)
+			go func(key [2]int) {
+				defer wg.Done()
+				limiter <- struct{}{}
+				defer func() { <-limiter }()
+
+				size, err := headSize(ctx, client, PatchURL(channel, key[0], key[1]))
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				sizes[key] = size
+				mu.Unlock()
+			}(key)
+		}
+	}
+
+	wg.Wait()
+	return sizes
+}
+
+// headSize returns the Content-Length of a URL, or an error when it does not exist
+func headSize(ctx context.Context, client *http.Client, url string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.ContentLength, nil
+}
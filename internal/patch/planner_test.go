@@ -0,0 +1,177 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+// servePatches answers HEAD requests for the given patches, keyed "from/to", with their sizes.
+// A negative size is sent without a Content-Length.
+func servePatches(t *testing.T, channel string, patches map[string]int64) {
+	t.Helper()
+	prefix := fmt.Sprintf("/%s/%s/%s/", runtime.GOOS, runtime.GOARCH, channel)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		var from, to int
+		if _, err := fmt.Sscanf(r.URL.Path, prefix+"%d/%d.pwr", &from, &to); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		size, ok := patches[fmt.Sprintf("%d/%d", from, to)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if size < 0 {
+			// Chunked responses carry no length
+			w.Header().Set("Transfer-Encoding", "chunked")
+		} else {
+			w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	old := patchBaseURL
+	patchBaseURL = srv.URL
+	t.Cleanup(func() { patchBaseURL = old })
+}
+
+// route lists a plan's steps as "from/to"
+func route(plan *UpdatePlan) []string {
+	var steps []string
+	for _, step := range plan.Steps {
+		steps = append(steps, fmt.Sprintf("%d/%d", step.From, step.To))
+	}
+	return steps
+}
+
+func TestPlanUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+		patches  map[string]int64
+		kind     string
+		steps    []string
+		total    int64
+	}{
+		{
+			name:    "fresh install",
+			from:    0,
+			to:      5,
+			patches: map[string]int64{"0/5": 1000},
+			kind:    RouteFull,
+			steps:   []string{"0/5"},
+			total:   1000,
+		},
+		{
+			name:    "downgrade installs the full build",
+			from:    7,
+			to:      5,
+			patches: map[string]int64{"0/5": 1000, "7/5": 10},
+			kind:    RouteFull,
+			steps:   []string{"0/5"},
+			total:   1000,
+		},
+		{
+			name:    "direct patch is smallest",
+			from:    3,
+			to:      5,
+			patches: map[string]int64{"0/5": 1000, "3/5": 50, "3/4": 40, "4/5": 40},
+			kind:    RouteDirect,
+			steps:   []string{"3/5"},
+			total:   50,
+		},
+		{
+			name:    "chain is smallest",
+			from:    3,
+			to:      5,
+			patches: map[string]int64{"0/5": 1000, "3/5": 500, "3/4": 40, "4/5": 40},
+			kind:    RouteChain,
+			steps:   []string{"3/4", "4/5"},
+			total:   80,
+		},
+		{
+			name:    "incomplete chain is skipped",
+			from:    3,
+			to:      6,
+			patches: map[string]int64{"0/6": 1000, "3/4": 1, "5/6": 1},
+			kind:    RouteFull,
+			steps:   []string{"0/6"},
+			total:   1000,
+		},
+		{
+			name:    "fewer steps break ties",
+			from:    3,
+			to:      5,
+			patches: map[string]int64{"0/5": 1000, "3/5": 80, "3/4": 40, "4/5": 40},
+			kind:    RouteDirect,
+			steps:   []string{"3/5"},
+			total:   80,
+		},
+		{
+			name:    "known sizes before unknown ones",
+			from:    3,
+			to:      4,
+			patches: map[string]int64{"0/4": 1000, "3/4": -1},
+			kind:    RouteFull,
+			steps:   []string{"0/4"},
+			total:   1000,
+		},
+		{
+			name:    "unknown size when nothing else exists",
+			from:    3,
+			to:      4,
+			patches: map[string]int64{"3/4": -1},
+			kind:    RouteDirect,
+			steps:   []string{"3/4"},
+			total:   -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servePatches(t, "release", tt.patches)
+
+			plan, err := PlanUpdate(context.Background(), "release", tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("PlanUpdate: %v", err)
+			}
+			if plan.Kind != tt.kind || plan.TotalSize != tt.total || !reflect.DeepEqual(route(plan), tt.steps) {
+				t.Errorf("plan = %s %v, %d bytes, want %s %v, %d bytes", plan.Kind, route(plan), plan.TotalSize, tt.kind, tt.steps, tt.total)
+			}
+			if want := PatchURL("release", plan.Steps[0].From, plan.Steps[0].To); plan.Steps[0].URL != want {
+				t.Errorf("step URL = %s, want %s", plan.Steps[0].URL, want)
+			}
+		})
+	}
+}
+
+func TestPlanUpdateNoRoute(t *testing.T) {
+	servePatches(t, "beta", map[string]int64{"0/4": 1000})
+
+	if _, err := PlanUpdate(context.Background(), "beta", 3, 5); err == nil {
+		t.Error("PlanUpdate found a route that does not exist")
+	}
+	if _, err := PlanUpdate(context.Background(), "beta", 0, 0); err == nil {
+		t.Error("PlanUpdate accepted version 0")
+	}
+}
+
+func TestPlanUpdateCancelled(t *testing.T) {
+	servePatches(t, "release", map[string]int64{"0/5": 1000})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := PlanUpdate(ctx, "release", 0, 5); err != context.Canceled {
+		t.Errorf("PlanUpdate error = %v, want context.Canceled", err)
+	}
+}
@@ -35,7 +35,7 @@ var (
 )
 
 func GetLocalVersion(channel string) string {
-	path := filepath.Join(env.GetDefaultAppDir(), channel, "version.json")
+	path := filepath.Join(env.GetChannelDir(channel), "version.json")
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return "0"
@@ -50,7 +50,7 @@ func GetLocalVersion(channel string) string {
 }
 
 func SaveLocalVersion(channel string, v int) error {
-	path := filepath.Join(env.GetDefaultAppDir(), channel, "version.json")
+	path := filepath.Join(env.GetChannelDir(channel), "version.json")
 	_ = os.MkdirAll(filepath.Dir(path), 0755)
 	data, _ := json.Marshal(VersionInfo{Version: v})
 	return os.WriteFile(path, data, 0644)
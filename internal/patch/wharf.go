@@ -0,0 +1,342 @@
+package patch
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// The wharf patch format, as written by butler diff:
+//
+//	int32 magic (little endian)
+//	PatchHeader                  uncompressed
+//	-- rest compressed as the header says --
+//	Container target             the old build
+//	Container source             the new build
+//	for every source file, in order:
+//	  SyncHeader
+//	  RSYNC:  SyncOp... up to HEY_YOU_DID_IT
+//	  BSDIFF: BsdiffHeader, Control... up to eof, SyncOp HEY_YOU_DID_IT
+//
+// Every message is protobuf, prefixed by its length as a uvarint. The few
+// messages needed are decoded by hand below.
+
+const patchMagic int32 = 0xFEF5000
+
+// Blocks addressed by rsync BLOCK_RANGE operations
+const wharfBlockSize = 64 * 1024
+
+// No message in a patch comes close, anything larger means a corrupt file
+const maxMessageSize = 256 << 20
+
+// Compression algorithms of CompressionSettings
+const (
+	compressionNone   = 0
+	compressionBrotli = 1
+	compressionGzip   = 2
+	compressionZstd   = 3
+)
+
+// SyncHeader types
+const (
+	syncRsync  = 0
+	syncBsdiff = 1
+)
+
+// SyncOp types
+const (
+	opBlockRange  = 0
+	opData        = 1
+	opHeyYouDidIt = 2049
+)
+
+var errCorruptPatch = errors.New("corrupt patch")
+
+type wharfContainer struct {
+	Dirs     []wharfEntry
+	Files    []wharfEntry
+	Symlinks []wharfEntry
+	Size     int64
+}
+
+// wharfEntry is a Dir, File or Symlink of a container
+type wharfEntry struct {
+	Path string
+	Mode uint32
+	Size int64
+	Dest string
+}
+
+type syncHeader struct {
+	Type      int64
+	FileIndex int64
+}
+
+type syncOp struct {
+	Type       int64
+	FileIndex  int64
+	BlockIndex int64
+	BlockSpan  int64
+	Data       []byte
+}
+
+type bsdiffControl struct {
+	Add  []byte
+	Copy []byte
+	Seek int64
+	Eof  bool
+}
+
+// wireReader reads length-prefixed messages, reusing one buffer
+type wireReader struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+func (w *wireReader) next() ([]byte, error) {
+	size, err := binary.ReadUvarint(w.r)
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if size > maxMessageSize {
+		return nil, fmt.Errorf("%w: %d byte message", errCorruptPatch, size)
+	}
+
+	if uint64(cap(w.buf)) < size {
+		w.buf = make([]byte, size)
+	}
+	w.buf = w.buf[:size]
+	if _, err := io.ReadFull(w.r, w.buf); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+// protoField is one decoded field, bytes alias the message buffer
+type protoField struct {
+	Num    int
+	Varint uint64
+	Bytes  []byte
+}
+
+// eachField walks the fields of a protobuf message
+func eachField(b []byte, fn func(f protoField) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errCorruptPatch
+		}
+		b = b[n:]
+
+		f := protoField{Num: int(tag >> 3)}
+		switch tag & 7 {
+		case 0: // varint
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				return errCorruptPatch
+			}
+			f.Varint = v
+			b = b[n:]
+		case 1: // 64 bit
+			if len(b) < 8 {
+				return errCorruptPatch
+			}
+			f.Varint = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case 2: // length delimited
+			size, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < size {
+				return errCorruptPatch
+			}
+			f.Bytes = b[n : n+int(size)]
+			b = b[n+int(size):]
+		case 5: // 32 bit
+			if len(b) < 4 {
+				return errCorruptPatch
+			}
+			f.Varint = uint64(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		default:
+			return fmt.Errorf("%w: wire type %d", errCorruptPatch, tag&7)
+		}
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeCompression(b []byte) (algorithm int64, err error) {
+	// PatchHeader { CompressionSettings compression = 1 }
+	// CompressionSettings { algorithm = 1, quality = 2 }
+	err = eachField(b, func(f protoField) error {
+		if f.Num != 1 {
+			return nil
+		}
+		return eachField(f.Bytes, func(s protoField) error {
+			if s.Num == 1 {
+				algorithm = int64(s.Varint)
+			}
+			return nil
+		})
+	})
+	return algorithm, err
+}
+
+// decodeContainer reads a tlc.Container, numbered as in tlc.proto:
+// files = 1, dirs = 2, symlinks = 3, size = 16
+func decodeContainer(b []byte) (*wharfContainer, error) {
+	c := &wharfContainer{}
+	err := eachField(b, func(f protoField) error {
+		switch f.Num {
+		case 1, 2, 3:
+			entry, err := decodeEntry(f.Bytes)
+			if err != nil {
+				return err
+			}
+			switch f.Num {
+			case 1:
+				c.Files = append(c.Files, entry)
+			case 2:
+				c.Dirs = append(c.Dirs, entry)
+			case 3:
+				c.Symlinks = append(c.Symlinks, entry)
+			}
+		case 16:
+			c.Size = int64(f.Varint)
+		}
+		return nil
+	})
+	return c, err
+}
+
+func decodeEntry(b []byte) (wharfEntry, error) {
+	// File { path = 1, mode = 2, size = 3, offset = 4 }
+	// Dir { path = 1, mode = 2 }
+	// Symlink { path = 1, mode = 2, dest = 3 }
+	var e wharfEntry
+	var field3 protoField
+	err := eachField(b, func(f protoField) error {
+		switch f.Num {
+		case 1:
+			e.Path = string(f.Bytes)
+		case 2:
+			e.Mode = uint32(f.Varint)
+		case 3:
+			field3 = f
+		}
+		return nil
+	})
+	if field3.Bytes != nil {
+		e.Dest = string(field3.Bytes)
+	} else {
+		e.Size = int64(field3.Varint)
+	}
+	if err == nil {
+		err = checkEntryPath(e.Path)
+	}
+	return e, err
+}
+
+// checkEntryPath keeps a patch from writing outside the install directory
+func checkEntryPath(p string) error {
+	clean := path.Clean(p)
+	if p == "" || path.IsAbs(p) || strings.Contains(p, "\\") || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("%w: invalid path %q", errCorruptPatch, p)
+	}
+	return nil
+}
+
+func decodeSyncHeader(b []byte) (syncHeader, error) {
+	var h syncHeader
+	err := eachField(b, func(f protoField) error {
+		switch f.Num {
+		case 1:
+			h.Type = int64(f.Varint)
+		case 16:
+			h.FileIndex = int64(f.Varint)
+		}
+		return nil
+	})
+	return h, err
+}
+
+func decodeSyncOp(b []byte) (syncOp, error) {
+	var op syncOp
+	err := eachField(b, func(f protoField) error {
+		switch f.Num {
+		case 1:
+			op.Type = int64(f.Varint)
+		case 2:
+			op.FileIndex = int64(f.Varint)
+		case 3:
+			op.BlockIndex = int64(f.Varint)
+		case 4:
+			op.BlockSpan = int64(f.Varint)
+		case 5:
+			op.Data = f.Bytes
+		}
+		return nil
+	})
+	return op, err
+}
+
+func decodeBsdiffHeader(b []byte) (targetIndex int64, err error) {
+	err = eachField(b, func(f protoField) error {
+		if f.Num == 1 {
+			targetIndex = int64(f.Varint)
+		}
+		return nil
+	})
+	return targetIndex, err
+}
+
+func decodeControl(b []byte) (bsdiffControl, error) {
+	var c bsdiffControl
+	err := eachField(b, func(f protoField) error {
+		switch f.Num {
+		case 1:
+			c.Add = f.Bytes
+		case 2:
+			c.Copy = f.Bytes
+		case 3:
+			c.Seek = int64(f.Varint)
+		case 4:
+			c.Eof = f.Varint != 0
+		}
+		return nil
+	})
+	return c, err
+}
+
+// decompressor wraps the compressed part of a patch
+func decompressor(algorithm int64, r io.Reader) (io.ReadCloser, error) {
+	switch algorithm {
+	case compressionNone:
+		return io.NopCloser(r), nil
+	case compressionBrotli:
+		return io.NopCloser(brotli.NewReader(r)), nil
+	case compressionGzip:
+		return gzip.NewReader(r)
+	case compressionZstd:
+		dec, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported patch compression %d", algorithm)
+	}
+}
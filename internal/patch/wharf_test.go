@@ -0,0 +1,382 @@
+package patch
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// The encoder below writes the wharf format described in wharf.go, so the
+// applier can be tested without butler. It makes the same kinds of messages
+// butler diff does, with a simpler choice of operations.
+
+// node is one entry of a test tree: a file, a symlink or a directory
+type node struct {
+	Data string
+	Link string
+	Dir  bool
+	Exec bool
+}
+
+// tree maps slash separated paths to their entries
+type tree map[string]node
+
+// protoBuf builds a protobuf message
+type protoBuf []byte
+
+func (b protoBuf) varint(num int, v uint64) protoBuf {
+	b = binary.AppendUvarint(b, uint64(num)<<3)
+	return binary.AppendUvarint(b, v)
+}
+
+func (b protoBuf) bytes(num int, data []byte) protoBuf {
+	b = binary.AppendUvarint(b, uint64(num)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// encodeOptions pick how the encoder writes a patch
+type encodeOptions struct {
+	Compression int64
+	Bsdiff      map[string]bool // new files written as bsdiff against the old file at the same path
+}
+
+// container lists a tree the way wharf does: every directory, then files and
+// symlinks, each sorted by path
+func container(t tree) (dirs, files, links []string) {
+	seen := make(map[string]bool)
+	addDir := func(p string) {
+		for p != "." && p != "/" && !seen[p] {
+			seen[p] = true
+			dirs = append(dirs, p)
+			p = path.Dir(p)
+		}
+	}
+	for p, n := range t {
+		switch {
+		case n.Dir:
+			addDir(p)
+		case n.Link != "":
+			links = append(links, p)
+			addDir(path.Dir(p))
+		default:
+			files = append(files, p)
+			addDir(path.Dir(p))
+		}
+	}
+	sort.Strings(dirs)
+	sort.Strings(files)
+	sort.Strings(links)
+	return dirs, files, links
+}
+
+func encodeContainer(t tree) []byte {
+	dirs, files, links := container(t)
+
+	var msg protoBuf
+	for _, p := range dirs {
+		msg = msg.bytes(2, protoBuf(nil).bytes(1, []byte(p)).varint(2, 0755))
+	}
+	var offset, size int64
+	for _, p := range files {
+		mode := uint64(0644)
+		if t[p].Exec {
+			mode = 0755
+		}
+		n := int64(len(t[p].Data))
+		msg = msg.bytes(1, protoBuf(nil).bytes(1, []byte(p)).varint(2, mode).varint(3, uint64(n)).varint(4, uint64(offset)))
+		offset += n
+		size += n
+	}
+	for _, p := range links {
+		msg = msg.bytes(3, protoBuf(nil).bytes(1, []byte(p)).varint(2, 0777).bytes(3, []byte(t[p].Link)))
+	}
+	return msg.varint(16, uint64(size))
+}
+
+// encodePatch writes a patch that turns old into new
+func encodePatch(old, new tree, opts encodeOptions) []byte {
+	var body bytes.Buffer
+	writeMsg := func(b []byte) { body.Write(wireMessages(b)) }
+	op := func(typ int64) protoBuf { return protoBuf(nil).varint(1, uint64(typ)) }
+
+	writeMsg(encodeContainer(old))
+	writeMsg(encodeContainer(new))
+
+	_, oldFiles, _ := container(old)
+	_, newFiles, _ := container(new)
+	oldIndex := make(map[string]int, len(oldFiles))
+	for i, p := range oldFiles {
+		oldIndex[p] = i
+	}
+
+	for i, p := range newFiles {
+		data := []byte(new[p].Data)
+		oldI, hasOld := oldIndex[p]
+
+		if opts.Bsdiff[p] && hasOld {
+			writeMsg(protoBuf(nil).varint(1, syncBsdiff).varint(16, uint64(i)))
+			writeMsg(protoBuf(nil).varint(1, uint64(oldI)))
+			for _, ctrl := range bsdiffControls([]byte(old[p].Data), data) {
+				writeMsg(ctrl)
+			}
+			writeMsg(protoBuf(nil).varint(4, 1))
+			writeMsg(op(opHeyYouDidIt))
+			continue
+		}
+
+		writeMsg(protoBuf(nil).varint(1, syncRsync).varint(16, uint64(i)))
+		for _, r := range rsyncRuns(old, oldFiles, data) {
+			if r.file < 0 {
+				writeMsg(op(opData).bytes(5, r.data))
+			} else {
+				writeMsg(op(opBlockRange).varint(2, uint64(r.file)).varint(3, uint64(r.block)).varint(4, uint64(r.span)))
+			}
+		}
+		writeMsg(op(opHeyYouDidIt))
+	}
+
+	return framePatch(opts.Compression, body.Bytes())
+}
+
+// wireMessages prefixes every message with its length
+func wireMessages(msgs ...[]byte) []byte {
+	var body []byte
+	for _, m := range msgs {
+		body = binary.AppendUvarint(body, uint64(len(m)))
+		body = append(body, m...)
+	}
+	return body
+}
+
+// framePatch writes the magic and header, then the compressed body
+func framePatch(compression int64, body []byte) []byte {
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, patchMagic)
+	header := protoBuf(nil).bytes(1, protoBuf(nil).varint(1, uint64(compression)).varint(2, 1))
+	out.Write(wireMessages(header))
+
+	var w io.WriteCloser
+	switch compression {
+	case compressionBrotli:
+		w = brotli.NewWriterLevel(&out, 1)
+	case compressionGzip:
+		w = gzip.NewWriter(&out)
+	case compressionZstd:
+		w, _ = zstd.NewWriter(&out)
+	default:
+		// Unknown algorithms are written as is, for tests expecting the error
+		out.Write(body)
+		return out.Bytes()
+	}
+	w.Write(body)
+	w.Close()
+	return out.Bytes()
+}
+
+// rsyncRun is a block range of an old file, or literal data when file is -1
+type rsyncRun struct {
+	file, block, span int
+	data              []byte
+}
+
+// rsyncRuns reuses every block of data found at the same index of an old
+// file, merging neighbours, and sends the rest as literal data
+func rsyncRuns(old tree, oldFiles []string, data []byte) []rsyncRun {
+	var runs []rsyncRun
+	for block := 0; block*wharfBlockSize < len(data); block++ {
+		chunk := data[block*wharfBlockSize : min((block+1)*wharfBlockSize, len(data))]
+
+		file := -1
+		for i, p := range oldFiles {
+			o := old[p].Data
+			start := block * wharfBlockSize
+			if start < len(o) && o[start:min(start+wharfBlockSize, len(o))] == string(chunk) {
+				file = i
+				break
+			}
+		}
+
+		if n := len(runs); n > 0 {
+			last := &runs[n-1]
+			if file < 0 && last.file < 0 {
+				last.data = append(last.data, chunk...)
+				continue
+			}
+			if file >= 0 && last.file == file && last.block+last.span == block {
+				last.span++
+				continue
+			}
+		}
+		if file < 0 {
+			runs = append(runs, rsyncRun{file: -1, data: append([]byte{}, chunk...)})
+		} else {
+			runs = append(runs, rsyncRun{file: file, block: block, span: 1})
+		}
+	}
+	return runs
+}
+
+// bsdiffControls diffs the first half of new against the start of old, skips
+// a few old bytes and diffs the rest, reading past the end of old on purpose
+func bsdiffControls(old, new []byte) [][]byte {
+	oldAt := func(i int) byte {
+		if i >= 0 && i < len(old) {
+			return old[i]
+		}
+		return 0
+	}
+	add := func(newFrom, newTo, oldFrom int) []byte {
+		diff := make([]byte, newTo-newFrom)
+		for j := range diff {
+			diff[j] = new[newFrom+j] - oldAt(oldFrom+j)
+		}
+		return diff
+	}
+
+	half, seek := len(new)/2, 3
+	tail := len(new) - len(new)/4
+	return [][]byte{
+		protoBuf(nil).bytes(1, add(0, half, 0)).varint(3, uint64(seek)),
+		protoBuf(nil).bytes(1, add(half, tail, half+seek)).bytes(2, new[tail:]),
+	}
+}
+
+func TestWireReader(t *testing.T) {
+	var b bytes.Buffer
+	b.Write(binary.AppendUvarint(nil, 3))
+	b.WriteString("abc")
+	b.Write(binary.AppendUvarint(nil, maxMessageSize+1))
+
+	w := &wireReader{r: bufio.NewReader(&b)}
+	if msg, err := w.next(); err != nil || string(msg) != "abc" {
+		t.Fatalf("next() = %q, %v", msg, err)
+	}
+	if _, err := w.next(); !errors.Is(err, errCorruptPatch) {
+		t.Errorf("oversized message error = %v, want errCorruptPatch", err)
+	}
+	if _, err := w.next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("read past the end error = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestEachFieldCorrupt(t *testing.T) {
+	tests := map[string][]byte{
+		"truncated tag":    {0x80},
+		"truncated varint": {0x08, 0x80},
+		"short bytes":      {0x0a, 0x05, 'a'},
+		"short fixed64":    {0x09, 1, 2, 3},
+		"short fixed32":    {0x0d, 1},
+		"group wire type":  {0x0b},
+	}
+	for name, b := range tests {
+		if err := eachField(b, func(protoField) error { return nil }); !errors.Is(err, errCorruptPatch) {
+			t.Errorf("%s: error = %v, want errCorruptPatch", name, err)
+		}
+	}
+}
+
+func TestDecodeContainer(t *testing.T) {
+	in := tree{
+		"Client/HytaleClient": {Data: "client", Exec: true},
+		"Client/Data/a.bin":   {Data: "aaaa"},
+		"lib/libfoo.so":       {Link: "libfoo.so.1"},
+		"Empty":               {Dir: true},
+	}
+	c, err := decodeContainer(encodeContainer(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dirs, files []string
+	for _, e := range c.Dirs {
+		dirs = append(dirs, e.Path)
+	}
+	for _, e := range c.Files {
+		files = append(files, e.Path)
+	}
+	if want := []string{"Client", "Client/Data", "Empty", "lib"}; strings.Join(dirs, ",") != strings.Join(want, ",") {
+		t.Errorf("dirs = %q, want %q", dirs, want)
+	}
+	if want := []string{"Client/Data/a.bin", "Client/HytaleClient"}; strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %q, want %q", files, want)
+	}
+	if c.Files[1].Size != 6 || c.Files[1].Mode != 0755 || c.Size != 10 {
+		t.Errorf("file = %+v, container size %d", c.Files[1], c.Size)
+	}
+	if len(c.Symlinks) != 1 || c.Symlinks[0].Dest != "libfoo.so.1" {
+		t.Errorf("symlinks = %+v", c.Symlinks)
+	}
+}
+
+func TestCheckEntryPath(t *testing.T) {
+	tests := []struct {
+		path string
+		ok   bool
+	}{
+		{"Client/HytaleClient", true},
+		{"a/b/../c", true},
+		{"..data", true},
+		{"a/..", false},
+		{".", false},
+		{"", false},
+		{"..", false},
+		{"../escape", false},
+		{"a/../../escape", false},
+		{"/etc/passwd", false},
+		{`..\escape`, false},
+		{`C:\Windows`, false},
+		{`a\b`, false},
+	}
+	for _, tt := range tests {
+		err := checkEntryPath(tt.path)
+		if (err == nil) != tt.ok {
+			t.Errorf("checkEntryPath(%q) = %v, want ok %v", tt.path, err, tt.ok)
+		}
+		if err != nil && !errors.Is(err, errCorruptPatch) {
+			t.Errorf("checkEntryPath(%q) error %v is not errCorruptPatch", tt.path, err)
+		}
+	}
+}
+
+func TestDecodeContainerFieldNumbers(t *testing.T) {
+	// Written by hand from tlc.proto, independent of encodeContainer:
+	// files = 1 { path "a", mode 0644, size 3 }, dirs = 2 { path "d", mode 0755 }, size = 16
+	golden := []byte{
+		0x0a, 0x08, 0x0a, 0x01, 'a', 0x10, 0xa4, 0x03, 0x18, 0x03,
+		0x12, 0x06, 0x0a, 0x01, 'd', 0x10, 0xed, 0x03,
+		0x80, 0x01, 0x03,
+	}
+	c, err := decodeContainer(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Files) != 1 || c.Files[0].Path != "a" || c.Files[0].Mode != 0644 || c.Files[0].Size != 3 {
+		t.Errorf("Files = %+v, want a (0644, 3 bytes)", c.Files)
+	}
+	if len(c.Dirs) != 1 || c.Dirs[0].Path != "d" || c.Dirs[0].Mode != 0755 {
+		t.Errorf("Dirs = %+v, want d (0755)", c.Dirs)
+	}
+	if c.Size != 3 {
+		t.Errorf("Size = %d, want 3", c.Size)
+	}
+}
+
+func TestDecodeEntryRejectsTraversal(t *testing.T) {
+	for _, p := range []string{"../../.bashrc", "/tmp/x", `..\x`} {
+		entry := protoBuf(nil).bytes(1, []byte(p)).varint(2, 0644).varint(3, 1)
+		if _, err := decodeContainer(protoBuf(nil).bytes(1, entry)); !errors.Is(err, errCorruptPatch) {
+			t.Errorf("container with %q: error = %v, want errCorruptPatch", p, err)
+		}
+	}
+}
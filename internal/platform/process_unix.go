@@ -0,0 +1,37 @@
+//go:build !windows
+
+package platform
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// SetProcessGroup starts the command in its own process group, so it and the
+// processes it spawns can be signalled together
+func SetProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// TerminateProcessGroup asks every process in the group to exit
+func TerminateProcessGroup(pid int) error {
+	return signalGroup(pid, syscall.SIGTERM)
+}
+
+// KillProcessGroup kills every process left in the group
+func KillProcessGroup(pid int) error {
+	return signalGroup(pid, syscall.SIGKILL)
+}
+
+func signalGroup(pid int, sig syscall.Signal) error {
+	err := syscall.Kill(-pid, sig)
+	if errors.Is(err, syscall.ESRCH) {
+		// Group is already gone
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package platform
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// SetProcessGroup starts the command in its own process group, so it and the
+// processes it spawns can be signalled together
+func SetProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// TerminateProcessGroup asks the process tree to close its windows and exit
+func TerminateProcessGroup(pid int) error {
+	return taskkill("/T", "/PID", strconv.Itoa(pid))
+}
+
+// KillProcessGroup forcefully ends the whole process tree
+func KillProcessGroup(pid int) error {
+	return taskkill("/T", "/F", "/PID", strconv.Itoa(pid))
+}
+
+func taskkill(args ...string) error {
+	cmd := exec.Command("taskkill", args...)
+	HideConsoleWindow(cmd)
+	return cmd.Run()
+}
@@ -19,6 +19,7 @@ const (
 	StageOnlineFix Stage = "online-fix"
 	StageLaunch    Stage = "launch"
 	StageUpdate    Stage = "update"
+	StageMove      Stage = "move"
 	StageComplete  Stage = "complete"
 )
 
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"HyLauncher/internal/config"
+	"HyLauncher/internal/env"
+	"HyLauncher/internal/game"
+	"HyLauncher/internal/java"
+	"HyLauncher/internal/platform"
+)
+
+const serverJar = "HytaleServer.jar"
+
+// Options describes a dedicated server launch
+type Options struct {
+	GameDir  string // game installation containing Server/ and Assets.zip
+	Settings config.ServerSettings
+	Log      *game.SessionLog
+}
+
+// Server is a running dedicated server with an open console
+type Server struct {
+	Cmd  *exec.Cmd
+	Port int
+
+	mu    sync.Mutex
+	stdin io.WriteCloser
+}
+
+// LogDir is where server console logs are kept
+func LogDir() string {
+	return filepath.Join(env.GetDefaultAppDir(), "logs", "server")
+}
+
+// Start runs HytaleServer.jar with the launcher's JRE
+func Start(opts Options) (*Server, error) {
+	serverDir := filepath.Join(opts.GameDir, "Server")
+	jarPath := filepath.Join(serverDir, serverJar)
+	if _, err := os.Stat(jarPath); err != nil {
+		return nil, fmt.Errorf("server files not found at %s, install the game first: %w", jarPath, err)
+	}
+
+	assetsPath := filepath.Join(opts.GameDir, "Assets.zip")
+	if _, err := os.Stat(assetsPath); err != nil {
+		return nil, fmt.Errorf("game assets not found at %s: %w", assetsPath, err)
+	}
+
+	javaBin, err := java.GetJavaExec()
+	if err != nil {
+		return nil, fmt.Errorf("java runtime unavailable: %w", err)
+	}
+
+	args, err := buildArgs(opts.Settings, assetsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(javaBin, args...)
+	cmd.Dir = serverDir
+
+	if opts.Log != nil {
+		cmd.Stdout = opts.Log.Writer("stdout")
+		cmd.Stderr = opts.Log.Writer("stderr")
+	} else {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open server console: %w", err)
+	}
+
+	platform.HideConsoleWindow(cmd)
+	platform.SetProcessGroup(cmd)
+
+	fmt.Printf("Starting server: %s %s\n", javaBin, game.JoinArgs(args))
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start server: %w", err)
+	}
+
+	return &Server{
+		Cmd:   cmd,
+		Port:  opts.Settings.Port,
+		stdin: stdin,
+	}, nil
+}
+
+func buildArgs(settings config.ServerSettings, assetsPath string) ([]string, error) {
+	var args []string
+
+	minMem, maxMem := settings.MinMemory, settings.MaxMemory
+	if maxMem > 0 && minMem > maxMem {
+		minMem = maxMem
+	}
+	if minMem > 0 {
+		args = append(args, fmt.Sprintf("-Xms%dG", minMem))
+	}
+	if maxMem > 0 {
+		args = append(args, fmt.Sprintf("-Xmx%dG", maxMem))
+	}
+
+	javaArgs, err := game.SplitArgs(settings.JavaArgs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server Java arguments: %w", err)
+	}
+	args = append(args, javaArgs...)
+
+	args = append(args, "-jar", serverJar, "--assets", assetsPath)
+
+	if settings.Port > 0 {
+		if settings.Port > 65535 {
+			return nil, fmt.Errorf("invalid server port %d", settings.Port)
+		}
+		args = append(args, "--bind", "0.0.0.0:"+strconv.Itoa(settings.Port))
+	}
+
+	extra, err := game.SplitArgs(settings.ExtraArgs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server arguments: %w", err)
+	}
+	args = append(args, extra...)
+
+	return args, nil
+}
+
+// SendCommand writes a command line to the server console
+func (s *Server) SendCommand(command string) error {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return nil
+	}
+	if strings.ContainsAny(command, "\r\n") {
+		return fmt.Errorf("server commands must be a single line")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stdin == nil {
+		return fmt.Errorf("server console is closed")
+	}
+
+	_, err := io.WriteString(s.stdin, command+"\n")
+	return err
+}
+
+// RequestStop asks the server to save and shut down through its console
+func (s *Server) RequestStop() error {
+	err := s.SendCommand("stop")
+
+	s.mu.Lock()
+	if s.stdin != nil {
+		_ = s.stdin.Close()
+		s.stdin = nil
+	}
+	s.mu.Unlock()
+
+	return err
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"HyLauncher/internal/config"
+)
+
+func TestBuildArgs(t *testing.T) {
+	const assets = "/games/Assets.zip"
+	tail := []string{"-jar", serverJar, "--assets", assets}
+
+	tests := []struct {
+		name     string
+		settings config.ServerSettings
+		want     []string
+		wantErr  bool
+	}{
+		{name: "defaults", settings: config.ServerSettings{}, want: tail},
+		{
+			name:     "memory",
+			settings: config.ServerSettings{MinMemory: 2, MaxMemory: 6},
+			want:     append([]string{"-Xms2G", "-Xmx6G"}, tail...),
+		},
+		{
+			name:     "min memory clamped to max",
+			settings: config.ServerSettings{MinMemory: 8, MaxMemory: 4},
+			want:     append([]string{"-Xms4G", "-Xmx4G"}, tail...),
+		},
+		{
+			name:     "min memory only",
+			settings: config.ServerSettings{MinMemory: 2},
+			want:     append([]string{"-Xms2G"}, tail...),
+		},
+		{
+			name:     "java args before the jar",
+			settings: config.ServerSettings{MaxMemory: 4, JavaArgs: `-XX:+UseG1GC "-Dname=my server"`},
+			want:     append([]string{"-Xmx4G", "-XX:+UseG1GC", "-Dname=my server"}, tail...),
+		},
+		{
+			name:     "port",
+			settings: config.ServerSettings{Port: 5520},
+			want:     append(append([]string{}, tail...), "--bind", "0.0.0.0:5520"),
+		},
+		{
+			name:     "highest port",
+			settings: config.ServerSettings{Port: 65535},
+			want:     append(append([]string{}, tail...), "--bind", "0.0.0.0:65535"),
+		},
+		{name: "port out of range", settings: config.ServerSettings{Port: 65536}, wantErr: true},
+		{
+			name:     "extra args after bind",
+			settings: config.ServerSettings{Port: 5520, ExtraArgs: `--auth-mode offline --name 'My Server'`},
+			want:     append(append([]string{}, tail...), "--bind", "0.0.0.0:5520", "--auth-mode", "offline", "--name", "My Server"),
+		},
+		{name: "bad java args", settings: config.ServerSettings{JavaArgs: `"-Dx`}, wantErr: true},
+		{name: "bad extra args", settings: config.ServerSettings{ExtraArgs: `'x`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildArgs(tt.settings, assets)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
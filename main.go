@@ -30,6 +30,7 @@ func main() {
 		},
 		BackgroundColour: &options.RGBA{R: 0, G: 0, B: 0, A: 255},
 		OnStartup:        application.Startup,
+		OnShutdown:       application.Shutdown,
 		CSSDragProperty:  "--wails-draggable",
 		Bind: []interface{}{
 			application,